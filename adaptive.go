@@ -0,0 +1,65 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// adaptiveBackoff is the factor an AdaptiveLimiter multiplies its limit by
+// on every slow or failed answer.
+const adaptiveBackoff = 0.5
+
+// AdaptiveLimiter tunes how many addresses Get queries at once from the
+// outcomes of recent attempts, the way TCP tunes its congestion window:
+// every healthy answer grows the limit additively, by one per limit answers,
+// and every failure or answer slower than the target latency halves it. The
+// limit stays between the bounds given to NewAdaptiveLimiter. An
+// AdaptiveLimiter is safe for concurrent use and is meant to be shared by
+// many Get calls.
+type AdaptiveLimiter struct {
+	mu       sync.Mutex
+	limit    float64
+	minLimit int
+	maxLimit int
+	target   time.Duration
+}
+
+// NewAdaptiveLimiter returns an AdaptiveLimiter starting at maxLimit and
+// counting answers slower than target as unhealthy; a target of zero or less
+// counts only failures.
+func NewAdaptiveLimiter(minLimit, maxLimit int, target time.Duration) *AdaptiveLimiter {
+	minLimit = max(minLimit, 1)
+	maxLimit = max(maxLimit, minLimit)
+	return &AdaptiveLimiter{limit: float64(maxLimit), minLimit: minLimit, maxLimit: maxLimit, target: target}
+}
+
+// WithAdaptiveConcurrency bounds every Get to the limit l currently yields,
+// in place of WithMaxConcurrency, and reports the outcome of every attempt
+// Get waits for back into l. Attempts cut short by the operation ending,
+// such as the losers of a race, are not reported.
+func WithAdaptiveConcurrency(l *AdaptiveLimiter) Option {
+	return func(c *config) {
+		c.adaptive = l
+	}
+}
+
+// Record adjusts the limit to the outcome of one attempt.
+func (l *AdaptiveLimiter) Record(latency time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err != nil || (l.target > 0 && latency > l.target) {
+		l.limit = max(l.limit*adaptiveBackoff, float64(l.minLimit))
+		return
+	}
+
+	l.limit = min(l.limit+1/l.limit, float64(l.maxLimit))
+}
+
+// Limit returns how many addresses may currently be queried at once.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return int(l.limit)
+}
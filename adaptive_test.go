@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	errConn := errors.New("connection error")
+	fast, slow := time.Millisecond, 50*time.Millisecond
+
+	type outcome struct {
+		latency time.Duration
+		err     error
+	}
+	repeat := func(o outcome, n int) []outcome {
+		var l []outcome
+		for range n {
+			l = append(l, o)
+		}
+		return l
+	}
+
+	tests := []struct {
+		name     string
+		minLimit int
+		outcomes []outcome
+		want     int
+	}{
+		{name: "без исходов — максимум", minLimit: 1, want: 8},
+		{name: "ошибки уменьшают вдвое", minLimit: 1, outcomes: repeat(outcome{fast, errConn}, 2), want: 2},
+		{name: "медленный ответ уменьшает", minLimit: 1, outcomes: []outcome{{slow, nil}}, want: 4},
+		{name: "не ниже минимума", minLimit: 3, outcomes: repeat(outcome{fast, errConn}, 5), want: 3},
+		{
+			name:     "здоровые ответы растят по одному за окно",
+			minLimit: 1,
+			outcomes: append(repeat(outcome{fast, errConn}, 2), repeat(outcome{fast, nil}, 3)...),
+			want:     3,
+		},
+		{name: "не выше максимума", minLimit: 1, outcomes: repeat(outcome{fast, nil}, 20), want: 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewAdaptiveLimiter(tt.minLimit, 8, 10*time.Millisecond)
+			for _, o := range tt.outcomes {
+				l.Record(o.latency, o.err)
+			}
+
+			if got := l.Limit(); got != tt.want {
+				t.Fatalf("Limit() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetWithAdaptiveConcurrency(t *testing.T) {
+	errConn := errors.New("connection error")
+	getter := newCountingGetter(NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errConn, Delay: 10 * time.Millisecond}},
+		"addr2": {"key1": {Error: errConn, Delay: 10 * time.Millisecond}},
+		"addr3": {"key1": {Error: errConn, Delay: 10 * time.Millisecond}},
+	}))
+	addresses := []string{"addr1", "addr2", "addr3"}
+	l := NewAdaptiveLimiter(1, 3, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if _, err := Get(ctx, getter, addresses, "key1", WithAdaptiveConcurrency(l)); !errors.Is(err, errConn) {
+		t.Fatalf("Get() error = %v, want %v", err, errConn)
+	}
+
+	if n := getter.MaxInFlight(); n != 3 {
+		t.Fatalf("max in flight = %d, want 3", n)
+	}
+
+	if n := l.Limit(); n != 1 {
+		t.Fatalf("Limit() after three failures = %d, want 1", n)
+	}
+
+	getter = newCountingGetter(NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errConn, Delay: 10 * time.Millisecond}},
+		"addr2": {"key1": {Value: "value2", Delay: 10 * time.Millisecond}},
+		"addr3": {"key1": {Value: "value3", Delay: 10 * time.Millisecond}},
+	}))
+	if got, err := Get(ctx, getter, addresses, "key1", WithAdaptiveConcurrency(l)); err != nil || got != "value2" {
+		t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value2")
+	}
+
+	if n := getter.MaxInFlight(); n != 1 {
+		t.Fatalf("max in flight with a shrunk limit = %d, want 1", n)
+	}
+}
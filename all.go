@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// AddressResult is the outcome of querying a single address.
+type AddressResult struct {
+	Address string
+	Value   string
+	Err     error
+	Latency time.Duration
+}
+
+// errPending marks the results GetAllInto is still waiting for.
+var errPending = errors.New("pending")
+
+// GetAll queries every address concurrently and waits for all of them,
+// returning one result per address in input order. The error is non-nil only
+// when every address failed or ctx was done before all of them answered. In
+// the latter case the results are still returned: the addresses that had
+// answered by then with their outcome, the others with the context error.
+func GetAll(ctx context.Context, getter Getter, addresses []string, key string) ([]AddressResult, error) {
+	return GetAllInto(ctx, getter, addresses, key, nil)
+}
+
+// GetAllInto works like GetAll but stores the results in dst, resliced to
+// their number and grown only if it is too small, so that a caller reusing
+// one buffer across calls does not allocate a new one every time. The
+// results are returned in the possibly reallocated buffer; whenever GetAll
+// would return nil results, it returns dst emptied.
+func GetAllInto(ctx context.Context, getter Getter, addresses []string, key string, dst []AddressResult) ([]AddressResult, error) {
+	dst = dst[:0]
+	if err := validate[string](getter, key); err != nil {
+		return dst, err
+	}
+
+	if len(addresses) == 0 {
+		return dst, nil
+	}
+
+	type indexed struct {
+		index int
+		AddressResult
+	}
+
+	all := slices.Grow(dst, len(addresses))[:len(addresses)]
+	for i, address := range addresses {
+		all[i] = AddressResult{Address: address, Err: errPending}
+	}
+
+	started := time.Now()
+	results := make(chan indexed, len(addresses))
+	for i, address := range addresses {
+		go func() {
+			start := time.Now()
+			value, err := query[string](ctx, getter, address, key, config{})
+			results <- indexed{index: i, AddressResult: AddressResult{
+				Address: address,
+				Value:   value,
+				Err:     err,
+				Latency: time.Since(start),
+			}}
+		}()
+	}
+
+	failed := 0
+	for range addresses {
+		select {
+		case r := <-results:
+			all[r.index] = r.AddressResult
+			if r.Err != nil {
+				failed++
+			}
+		case <-ctx.Done():
+			err := canceled(ctx)
+			for i := range all {
+				if all[i].Err == errPending {
+					all[i].Err, all[i].Latency = err, time.Since(started)
+				}
+			}
+
+			return all, err
+		}
+	}
+
+	if failed == len(addresses) {
+		errs := make([]AddressError, len(all))
+		for i, r := range all {
+			errs[i] = AddressError{Address: r.Address, Err: r.Err}
+		}
+
+		return all, &MultiError{Errors: errs}
+	}
+
+	return all, nil
+}
+
+// GetStream queries every address concurrently and emits each result on the
+// returned channel as soon as it arrives, in completion order. The channel is
+// closed once every address has answered or ctx is done, whichever comes
+// first. It is buffered for one result per address, so the attempts never
+// block on a consumer that stopped reading. When the arguments are invalid
+// no address is queried: the channel carries a single result, with no
+// address and the validation error, and is closed.
+func GetStream(ctx context.Context, getter Getter, addresses []string, key string) <-chan AddressResult {
+	if err := validate[string](getter, key); err != nil {
+		out := make(chan AddressResult, 1)
+		out <- AddressResult{Err: err}
+		close(out)
+		return out
+	}
+
+	out := make(chan AddressResult, len(addresses))
+	results := make(chan AddressResult, len(addresses))
+	for _, address := range addresses {
+		go func() {
+			start := time.Now()
+			value, err := query[string](ctx, getter, address, key, config{})
+			results <- AddressResult{Address: address, Value: value, Err: err, Latency: time.Since(start)}
+		}()
+	}
+
+	go func() {
+		defer close(out)
+
+		for range addresses {
+			select {
+			case r := <-results:
+				out <- r
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
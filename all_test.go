@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetAll(t *testing.T) {
+	errConn := errors.New("connection error")
+
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		addresses []string
+		ttl       time.Duration
+		want      []AddressResult
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "результаты всех адресов в порядке входа",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: 40 * time.Millisecond}},
+				"addr2": {"key1": {Error: errConn}},
+				"addr3": {"key1": {Value: "value3"}},
+			},
+			addresses: []string{"addr1", "addr2", "addr3"},
+			ttl:       time.Second,
+			want: []AddressResult{
+				{Address: "addr1", Value: "value1", Latency: 40 * time.Millisecond},
+				{Address: "addr2", Err: errConn},
+				{Address: "addr3", Value: "value3"},
+			},
+		},
+		{
+			name: "все адреса падают",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errConn}},
+				"addr2": {},
+			},
+			addresses: []string{"addr1", "addr2"},
+			ttl:       time.Second,
+			want: []AddressResult{
+				{Address: "addr1", Err: errConn},
+				{Address: "addr2", Err: errors.New("key not found")},
+			},
+			wantErr:   true,
+			wantErrIs: errConn,
+		},
+		{
+			name: "отмена контекста",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: 200 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			ttl:       50 * time.Millisecond,
+			wantErr:   true,
+			wantErrIs: context.Canceled,
+		},
+		{
+			name:      "пустой список адресов",
+			responses: map[string]map[string]Response{},
+			addresses: []string{},
+			ttl:       time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ttl)
+			defer cancel()
+
+			got, err := GetAll(ctx, NewMockGetter(tt.responses), tt.addresses, "key1")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("GetAll() error = %v, want errors.Is(err, %v) == true", err, tt.wantErrIs)
+			}
+
+			if tt.want == nil {
+				return
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetAll() returned %d results, want %d", len(got), len(tt.want))
+			}
+
+			for i, want := range tt.want {
+				r := got[i]
+				if r.Address != want.Address || r.Value != want.Value || (r.Err != nil) != (want.Err != nil) {
+					t.Fatalf("GetAll()[%d] = %+v, want %+v", i, r, want)
+				}
+
+				if want.Err != nil && r.Err.Error() != want.Err.Error() {
+					t.Fatalf("GetAll()[%d].Err = %v, want %v", i, r.Err, want.Err)
+				}
+
+				if r.Latency < want.Latency {
+					t.Fatalf("GetAll()[%d].Latency = %v, want at least %v", i, r.Latency, want.Latency)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllCancel(t *testing.T) {
+	errConn := errors.New("connection error")
+	mock := NewMockGetter(map[string]map[string]Response{
+		"slow": {"key1": {Value: "slow", Delay: time.Second}},
+		"fast": {"key1": {Value: "fast"}},
+		"bad":  {"key1": {Error: errConn}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	got, err := GetAll(ctx, mock, []string{"slow", "fast", "bad"}, "key1")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetAll() error = %v, want context.Canceled", err)
+	}
+
+	if len(got) != 3 {
+		t.Fatalf("GetAll() returned %d results, want 3", len(got))
+	}
+
+	if r := got[0]; r.Address != "slow" || !errors.Is(r.Err, context.Canceled) || r.Latency < 50*time.Millisecond {
+		t.Errorf("GetAll()[0] = %+v, want slow cancelled after 50ms", r)
+	}
+
+	if r := got[1]; r.Address != "fast" || r.Value != "fast" || r.Err != nil {
+		t.Errorf("GetAll()[1] = %+v, want fast answered", r)
+	}
+
+	if r := got[2]; r.Address != "bad" || !errors.Is(r.Err, errConn) {
+		t.Errorf("GetAll()[2] = %+v, want bad failed with its own error", r)
+	}
+}
+
+func TestGetAllInto(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1"}},
+		"addr2": {"key1": {Error: errors.New("connection error")}},
+		"addr3": {"key1": {Value: "value3"}},
+	})
+	addresses := []string{"addr1", "addr2", "addr3"}
+
+	tests := []struct {
+		name       string
+		dst        []AddressResult
+		wantReused bool
+	}{
+		{name: "без буфера", dst: nil},
+		{name: "буфер достаточного размера переиспользуется", dst: make([]AddressResult, 1, 4), wantReused: true},
+		{name: "маленький буфер растёт", dst: make([]AddressResult, 0, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetAllInto(context.Background(), mock, addresses, "key1", tt.dst)
+			if err != nil {
+				t.Fatalf("GetAllInto() error = %v", err)
+			}
+
+			if len(got) != len(addresses) {
+				t.Fatalf("GetAllInto() returned %d results, want %d", len(got), len(addresses))
+			}
+
+			for i, r := range got {
+				if r.Address != addresses[i] || (r.Err == nil) != (i != 1) {
+					t.Fatalf("GetAllInto()[%d] = %+v, want the result of %s", i, r, addresses[i])
+				}
+			}
+
+			if reused := cap(tt.dst) > 0 && &got[0] == &tt.dst[:1][0]; reused != tt.wantReused {
+				t.Fatalf("GetAllInto() reused dst = %v, want %v", reused, tt.wantReused)
+			}
+		})
+	}
+}
+
+func BenchmarkGetAll(b *testing.B) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1"}},
+		"addr2": {"key1": {Value: "value2"}},
+		"addr3": {"key1": {Value: "value3"}},
+	})
+	addresses := []string{"addr1", "addr2", "addr3"}
+	ctx := context.Background()
+
+	b.Run("GetAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := GetAll(ctx, mock, addresses, "key1"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("GetAllInto", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []AddressResult
+		for b.Loop() {
+			var err error
+			if buf, err = GetAllInto(ctx, mock, addresses, "key1", buf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestGetStream(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1", Delay: 40 * time.Millisecond}},
+		"addr2": {"key1": {Error: errors.New("connection error")}},
+		"addr3": {"key1": {Value: "value3", Delay: 100 * time.Millisecond}},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	var got []string
+	for r := range GetStream(ctx, mock, []string{"addr1", "addr2", "addr3"}, "key1") {
+		got = append(got, r.Address)
+	}
+
+	if want := []string{"addr2", "addr1", "addr3"}; !slices.Equal(got, want) {
+		t.Fatalf("GetStream() emitted %v, want %v", got, want)
+	}
+}
+
+func TestGetStreamInvalidArguments(t *testing.T) {
+	tests := []struct {
+		name      string
+		getter    Getter
+		key       string
+		wantErrIs error
+	}{
+		{name: "nil getter", key: "key1", wantErrIs: ErrNilGetter},
+		{name: "пустой ключ", getter: NewMockGetter(nil), key: "", wantErrIs: ErrEmptyKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []AddressResult
+			for r := range GetStream(context.Background(), tt.getter, []string{"addr1", "addr2"}, tt.key) {
+				got = append(got, r)
+			}
+
+			if len(got) != 1 || got[0].Address != "" || !errors.Is(got[0].Err, tt.wantErrIs) {
+				t.Fatalf("GetStream() emitted %+v, want a single %v", got, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestGetStreamCancel(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1"}},
+		"addr2": {"key1": {Value: "value2", Delay: time.Second}},
+		"addr3": {"key1": {Value: "value3", Delay: time.Second}},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := GetStream(ctx, mock, []string{"addr1", "addr2", "addr3"}, "key1")
+
+	first := <-stream
+	if first.Address != "addr1" || first.Value != "value1" || first.Err != nil {
+		t.Fatalf("first result = %+v, want value1 from addr1", first)
+	}
+
+	cancel()
+
+	timeout := time.After(200 * time.Millisecond)
+	for {
+		select {
+		case r, ok := <-stream:
+			if !ok {
+				return
+			}
+
+			if r.Err == nil {
+				t.Fatalf("got successful result %+v after cancel", r)
+			}
+		case <-timeout:
+			t.Fatal("GetStream() channel not closed after cancel")
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+)
+
+// Percentiles summarizes the latencies of repeated Get calls. ErrorRate is
+// the fraction of the calls that failed, between 0 and 1.
+type Percentiles struct {
+	P50, P95, P99 time.Duration
+	ErrorRate     float64
+}
+
+// Benchmark runs Get n times, one after the other, and summarizes how long
+// the calls took, failed ones included. It stops early, with the summary of
+// the calls made so far, once ctx is done or the arguments are invalid.
+func Benchmark(ctx context.Context, getter Getter, addresses []string, key string, n int, opts ...Option) (Percentiles, error) {
+	clock := newConfig(opts).timeSource()
+	latencies := make([]time.Duration, 0, max(n, 0))
+	failed := 0
+	for range n {
+		if ctx.Err() != nil {
+			return summarize(latencies, failed), canceled(ctx)
+		}
+
+		start := clock.Now()
+		_, err := Get(ctx, getter, addresses, key, opts...)
+		if errors.Is(err, ErrNilGetter) || errors.Is(err, ErrEmptyKey) {
+			return Percentiles{}, err
+		}
+
+		latencies = append(latencies, clock.Now().Sub(start))
+		if err != nil {
+			failed++
+		}
+	}
+
+	return summarize(latencies, failed), nil
+}
+
+func summarize(latencies []time.Duration, failed int) Percentiles {
+	if len(latencies) == 0 {
+		return Percentiles{}
+	}
+
+	slices.Sort(latencies)
+
+	return Percentiles{
+		P50:       percentile(latencies, 50),
+		P95:       percentile(latencies, 95),
+		P99:       percentile(latencies, 99),
+		ErrorRate: float64(failed) / float64(len(latencies)),
+	}
+}
+
+// percentile returns the p-th percentile of sorted, which must not be
+// empty, by the nearest-rank method: the smallest value no less than p
+// percent of the values.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBenchmark(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"fast":   {"key1": {Value: "value1", Delay: 10 * time.Millisecond}},
+		"broken": {"key1": {Error: errors.New("connection error"), Delay: 10 * time.Millisecond}},
+	})
+
+	tests := []struct {
+		name          string
+		addresses     []string
+		key           string
+		n             int
+		wantErrIs     error
+		wantErrorRate float64
+	}{
+		{name: "успешные вызовы", addresses: []string{"fast"}, key: "key1", n: 10},
+		{name: "все вызовы с ошибкой", addresses: []string{"broken"}, key: "key1", n: 5, wantErrorRate: 1},
+		{name: "пустой ключ", addresses: []string{"fast"}, n: 5, wantErrIs: ErrEmptyKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Benchmark(context.Background(), mock, tt.addresses, tt.key, tt.n)
+			if tt.wantErrIs != nil {
+				if !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("Benchmark() error = %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Benchmark() error = %v", err)
+			}
+
+			for _, p := range []time.Duration{got.P50, got.P95, got.P99} {
+				if p < 10*time.Millisecond || p > 100*time.Millisecond {
+					t.Fatalf("Benchmark() = %+v, want every percentile in [10ms, 100ms]", got)
+				}
+			}
+
+			if got.P50 > got.P95 || got.P95 > got.P99 {
+				t.Fatalf("Benchmark() = %+v, want P50 <= P95 <= P99", got)
+			}
+
+			if got.ErrorRate != tt.wantErrorRate {
+				t.Fatalf("Benchmark() error rate = %v, want %v", got.ErrorRate, tt.wantErrorRate)
+			}
+		})
+	}
+
+	t.Run("отмена контекста", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 35*time.Millisecond)
+		defer cancel()
+
+		got, err := Benchmark(ctx, mock, []string{"fast"}, "key1", 100)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Benchmark() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+
+		if got.P50 == 0 {
+			t.Fatalf("Benchmark() = %+v, want the calls made before the deadline summarized", got)
+		}
+	})
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		p      int
+		want   time.Duration
+	}{
+		{name: "медиана", sorted: latencies, p: 50, want: 50 * time.Millisecond},
+		{name: "p95", sorted: latencies, p: 95, want: 95 * time.Millisecond},
+		{name: "p99", sorted: latencies, p: 99, want: 99 * time.Millisecond},
+		{name: "одно значение", sorted: latencies[:1], p: 95, want: time.Millisecond},
+		{name: "округление вверх", sorted: latencies[:3], p: 50, want: 2 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.p); got != tt.want {
+				t.Fatalf("percentile(%d) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrAllCircuitsOpen = errors.New("circuit breakers of all addresses are open")
+	ErrCircuitOpen     = errors.New("circuit breaker is open")
+)
+
+// Breaker tracks consecutive failures per address across Get calls. Once an
+// address fails threshold times in a row its circuit opens and Get skips it
+// for cooldown. After that the circuit is half-open and a single call, the
+// probe, is let through: success closes the circuit, another failure opens
+// it again. While the probe runs every other Get skips the address too, and
+// a call that raced past the check fails with ErrCircuitOpen. A Breaker is
+// safe for concurrent use and is meant to be shared by many Get calls.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu    sync.Mutex
+	state map[string]*circuit
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: max(threshold, 1),
+		cooldown:  cooldown,
+		now:       time.Now,
+		state:     make(map[string]*circuit),
+	}
+}
+
+// WithBreaker makes Get skip addresses whose circuit in b is open and feeds
+// the outcome of every call back into b.
+func WithBreaker(b *Breaker) Option {
+	return func(c *config) {
+		c.breaker = b
+	}
+}
+
+// Open reports whether the circuit of address is currently open, or
+// half-open with its probe in flight.
+func (b *Breaker) Open(address string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.open(address, b.now())
+}
+
+func (b *Breaker) open(address string, now time.Time) bool {
+	c, ok := b.state[address]
+	return ok && (now.Before(c.openUntil) || c.probing)
+}
+
+// allow reports whether a call to address may go ahead, and whether it is
+// the probe of a half-open circuit. A probe is settled by record, or given
+// up by abandon.
+func (b *Breaker) allow(address string) (ok, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, found := b.state[address]
+	switch {
+	case !found || c.failures < b.threshold:
+		return true, false
+	case b.open(address, b.now()):
+		return false, false
+	default:
+		c.probing = true
+		return true, true
+	}
+}
+
+// abandon gives up the probe of address, so that another call can take it.
+func (b *Breaker) abandon(address string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if c, ok := b.state[address]; ok {
+		c.probing = false
+	}
+}
+
+// filter returns the addresses whose circuit is not open.
+func (b *Breaker) filter(addresses []string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	allowed := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if !b.open(address, now) {
+			allowed = append(allowed, address)
+		}
+	}
+
+	return allowed
+}
+
+func (b *Breaker) record(address string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err == nil {
+		delete(b.state, address)
+		return
+	}
+
+	c, ok := b.state[address]
+	if !ok {
+		c = &circuit{}
+		b.state[address] = c
+	}
+
+	c.failures++
+	c.probing = false
+	if c.failures >= b.threshold {
+		c.openUntil = b.now().Add(b.cooldown)
+	}
+}
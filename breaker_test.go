@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"bad":  {"key1": {Error: errors.New("connection error")}},
+		"good": {"key1": {Value: "value", Delay: 10 * time.Millisecond}},
+	})
+	getter := newCountingGetter(mock)
+
+	now := time.Now()
+	breaker := NewBreaker(3, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	get := func() {
+		t.Helper()
+
+		got, err := Get(context.Background(), getter, []string{"bad", "good"}, "key1", WithBreaker(breaker))
+		if err != nil || got != "value" {
+			t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value")
+		}
+	}
+
+	for range 3 {
+		get()
+	}
+
+	if !breaker.Open("bad") {
+		t.Fatal("breaker of bad address is closed after 3 failures, want open")
+	}
+
+	if breaker.Open("good") {
+		t.Fatal("breaker of good address is open, want closed")
+	}
+
+	get()
+	if n := getter.Calls("bad"); n != 3 {
+		t.Fatalf("calls to bad = %d, want 3: open address must be skipped", n)
+	}
+
+	now = now.Add(time.Minute)
+	get()
+	if n := getter.Calls("bad"); n != 4 {
+		t.Fatalf("calls to bad = %d, want 4: address must be retried after cooldown", n)
+	}
+
+	if !breaker.Open("bad") {
+		t.Fatal("breaker of bad address is closed after failing again, want open")
+	}
+}
+
+func TestBreakerAllOpen(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("error 1")}},
+		"addr2": {"key1": {Error: errors.New("error 2")}},
+	})
+	getter := newCountingGetter(mock)
+	breaker := NewBreaker(1, time.Minute)
+	addresses := []string{"addr1", "addr2"}
+
+	_, err := Get(context.Background(), getter, addresses, "key1", WithBreaker(breaker))
+	if err == nil || errors.Is(err, ErrAllCircuitsOpen) {
+		t.Fatalf("first Get() error = %v, want the backend errors", err)
+	}
+
+	_, err = Get(context.Background(), getter, addresses, "key1", WithBreaker(breaker))
+	if !errors.Is(err, ErrAllCircuitsOpen) {
+		t.Fatalf("second Get() error = %v, want %v", err, ErrAllCircuitsOpen)
+	}
+
+	if n := getter.TotalCalls(); n != 2 {
+		t.Fatalf("calls = %d, want 2", n)
+	}
+}
+
+func TestBreakerIgnoresCancelledLosers(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"slow": {"key1": {Value: "slow", Delay: time.Second}},
+		"fast": {"key1": {Value: "fast"}},
+	})
+	breaker := NewBreaker(1, time.Minute)
+
+	got, err := Get(context.Background(), mock, []string{"slow", "fast"}, "key1", WithBreaker(breaker))
+	if err != nil || got != "fast" {
+		t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "fast")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if breaker.Open("slow") {
+		t.Fatal("breaker of slow address opened because it lost the race")
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	setup := func(badDelay time.Duration) (*countingGetter, *Breaker, func(time.Duration)) {
+		mock := NewMockGetter(map[string]map[string]Response{
+			"bad":  {"key1": {Error: errors.New("connection error"), Delay: badDelay}},
+			"good": {"key1": {Value: "value", Delay: 20 * time.Millisecond}},
+		})
+
+		var mu sync.Mutex
+		now := time.Now()
+		breaker := NewBreaker(1, time.Minute)
+		breaker.now = func() time.Time {
+			mu.Lock()
+			defer mu.Unlock()
+			return now
+		}
+		breaker.record("bad", errors.New("connection error"))
+
+		advance := func(d time.Duration) {
+			mu.Lock()
+			defer mu.Unlock()
+			now = now.Add(d)
+		}
+
+		return newCountingGetter(mock), breaker, advance
+	}
+
+	t.Run("после паузы пропускается одна проба", func(t *testing.T) {
+		getter, breaker, advance := setup(50 * time.Millisecond)
+		advance(time.Minute)
+
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				got, err := Get(context.Background(), getter, []string{"bad", "good"}, "key1",
+					WithBreaker(breaker), WithAttemptTimeout(time.Second))
+				if err != nil || got != "value" {
+					t.Errorf("Get() = (%q, %v), want (%q, nil)", got, err, "value")
+				}
+			}()
+		}
+		wg.Wait()
+
+		if n := getter.Calls("bad"); n != 1 {
+			t.Fatalf("calls to bad = %d, want a single probe", n)
+		}
+	})
+
+	t.Run("брошенная проба освобождается", func(t *testing.T) {
+		getter, breaker, advance := setup(time.Second)
+		advance(time.Minute)
+
+		for want := 1; want <= 2; want++ {
+			got, err := Get(context.Background(), getter, []string{"bad", "good"}, "key1", WithBreaker(breaker))
+			if err != nil || got != "value" {
+				t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value")
+			}
+
+			// The probe is given up once the cancelled call returns.
+			for deadline := time.Now().Add(time.Second); breaker.Open("bad") && time.Now().Before(deadline); {
+				time.Sleep(time.Millisecond)
+			}
+
+			if n := getter.Calls("bad"); n != want {
+				t.Fatalf("calls to bad = %d, want %d", n, want)
+			}
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// WithDeadlineBudgeting splits the time left before the context deadline
+// between the addresses still to be queried, so that a slow or retrying
+// address cannot use up the whole deadline. An address gets its share of
+// the time left when it starts, given how many more start after it under
+// WithMaxConcurrency, and divides that share evenly between its remaining
+// attempts. Without a deadline on the context the option has no effect.
+func WithDeadlineBudgeting() Option {
+	return func(c *config) {
+		c.budgeting = true
+	}
+}
+
+// WithBudgetFloor caps the timeout of every attempt, as it starts, at the
+// time left before the context deadline, so a late attempt knows how little
+// it really has. An attempt about to start with less than floor left is not
+// started at all and fails its address with an error matching
+// context.DeadlineExceeded. Without a deadline on the context the option has
+// no effect.
+func WithBudgetFloor(floor time.Duration) Option {
+	return func(c *config) {
+		c.capAttempts = true
+		c.budgetFloor = floor
+	}
+}
+
+// rounds returns how many waves of at most limit attempts it takes to query
+// the pending addresses, the one starting now included.
+func rounds(pending, limit int) int {
+	return (pending + limit - 1) / limit
+}
+
+// addressBudget returns the moment by which an address sharing the rest of
+// the deadline of ctx over the given number of rounds should be done. It is
+// zero when ctx has no deadline.
+func addressBudget(ctx context.Context, rounds int) time.Time {
+	deadline, ok := ctx.Deadline()
+	if !ok || rounds <= 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(time.Until(deadline) / time.Duration(rounds))
+}
+
+// attemptBudget returns the timeout of the next attempt of an address with
+// the given attempts left before until, never longer than timeout when that
+// is positive.
+func attemptBudget(timeout time.Duration, until time.Time, attemptsLeft int) time.Duration {
+	share := max(time.Until(until)/time.Duration(attemptsLeft), time.Nanosecond)
+	if timeout > 0 {
+		return min(timeout, share)
+	}
+
+	return share
+}
+
+// remainingBudget returns timeout capped at the time left before the
+// deadline of ctx, or an error when less than floor is left.
+func remainingBudget(ctx context.Context, timeout, floor time.Duration) (time.Duration, error) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return timeout, nil
+	}
+
+	if err := checkDeadline(ctx, floor); err != nil {
+		return 0, err
+	}
+
+	if remaining := time.Until(deadline); timeout <= 0 || remaining < timeout {
+		return remaining, nil
+	}
+
+	return timeout, nil
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetWithDeadlineBudgeting(t *testing.T) {
+	addresses := []string{"addr1", "addr2", "addr3"}
+	responses := map[string]map[string]Response{}
+	for _, address := range addresses {
+		responses[address] = map[string]Response{"key1": {Value: "value", Delay: time.Second}}
+	}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantCalls map[string]int
+	}{
+		{
+			name:      "без распределения первый адрес съедает весь дедлайн",
+			opts:      []Option{WithMaxConcurrency(1), WithRetry(3)},
+			wantCalls: map[string]int{"addr1": 1, "addr2": 0, "addr3": 0},
+		},
+		{
+			name:      "с распределением каждый адрес получает попытки",
+			opts:      []Option{WithMaxConcurrency(1), WithRetry(3), WithDeadlineBudgeting()},
+			wantCalls: map[string]int{"addr1": 3, "addr2": 3, "addr3": 3},
+		},
+		{
+			name:      "параллельные адреса делят дедлайн на волны",
+			opts:      []Option{WithMaxConcurrency(2), WithDeadlineBudgeting()},
+			wantCalls: map[string]int{"addr1": 1, "addr2": 1, "addr3": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(responses))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+			defer cancel()
+
+			if _, err := Get(ctx, getter, addresses, "key1", tt.opts...); err == nil {
+				t.Fatal("Get() error = nil, want every address to run out of time")
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+// deadlineRecordingGetter records, per address, how much time the context
+// of its call had left when the call started, and then fails after delay.
+type deadlineRecordingGetter struct {
+	delay time.Duration
+
+	mu   sync.Mutex
+	left map[string]time.Duration
+}
+
+func (g *deadlineRecordingGetter) Get(ctx context.Context, address, key string) (string, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		g.mu.Lock()
+		g.left[address] = time.Until(deadline)
+		g.mu.Unlock()
+	}
+
+	if err := sleep(ctx, realClock{}, g.delay); err != nil {
+		return "", err
+	}
+
+	return "", errors.New("connection error")
+}
+
+func TestGetWithBudgetFloor(t *testing.T) {
+	getter := &deadlineRecordingGetter{delay: 60 * time.Millisecond, left: map[string]time.Duration{}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 150*time.Millisecond)
+	defer cancel()
+
+	opts := []Option{WithMaxConcurrency(1), WithAttemptTimeout(100 * time.Millisecond), WithBudgetFloor(40 * time.Millisecond)}
+	_, err := Get(ctx, getter, []string{"addr1", "addr2", "addr3"}, "key1", opts...)
+
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) || !errors.Is(multiErr.Errors[2].Err, context.DeadlineExceeded) {
+		t.Fatalf("Get() error = %v, want addr3 to fail for lack of time", err)
+	}
+
+	getter.mu.Lock()
+	defer getter.mu.Unlock()
+
+	early, late := getter.left["addr1"], getter.left["addr2"]
+	if early < 90*time.Millisecond || late > 95*time.Millisecond || late >= early {
+		t.Fatalf("time left for addr1 = %v and addr2 = %v, want about 100ms and 90ms", early, late)
+	}
+
+	if _, queried := getter.left["addr3"]; queried {
+		t.Fatal("addr3 was queried with less than the floor left")
+	}
+}
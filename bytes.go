@@ -0,0 +1,25 @@
+package main
+
+import (
+	"bytes"
+	"context"
+)
+
+// BytesGetter is a Getter for backends serving binary payloads, which are
+// returned as they are instead of going through a string.
+type BytesGetter interface {
+	Get(ctx context.Context, address, key string) ([]byte, error)
+}
+
+// GetBytes races addresses exactly like Get, but over binary values. The
+// returned slice is a copy of what the winning getter returned, so the
+// getter may reuse its buffers once the call is over.
+func GetBytes(ctx context.Context, bg BytesGetter, addresses []string, key string, opts ...Option) ([]byte, error) {
+	var getter TypedGetter[[]byte]
+	if bg != nil {
+		getter = bg
+	}
+
+	value, _, err := race(ctx, getter, addresses, key, newConfig(opts))
+	return bytes.Clone(value), err
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestGetBytes(t *testing.T) {
+	payload := []byte{0xff, 0xfe, 0x00, 0x80, 'a', 0xc3}
+	if utf8.Valid(payload) {
+		t.Fatal("test payload must not be valid UTF-8")
+	}
+
+	tests := []struct {
+		name      string
+		responses map[string]map[string]TypedResponse[[]byte]
+		opts      []Option
+		want      []byte
+		wantErrIs error
+	}{
+		{
+			name: "бинарные данные без искажений",
+			responses: map[string]map[string]TypedResponse[[]byte]{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+				"addr2": {"key1": {Value: payload, Delay: 10 * time.Millisecond}},
+			},
+			want: payload,
+		},
+		{
+			name: "пустое значение отклоняется",
+			responses: map[string]map[string]TypedResponse[[]byte]{
+				"addr1": {"key1": {Value: []byte{}}},
+				"addr2": {"key1": {Value: payload, Delay: 10 * time.Millisecond}},
+			},
+			opts: []Option{WithRejectEmpty()},
+			want: payload,
+		},
+		{
+			name: "все адреса упали",
+			responses: map[string]map[string]TypedResponse[[]byte]{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+			},
+			wantErrIs: ErrAllFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := &MockTypedGetter[[]byte]{Responses: tt.responses}
+
+			got, err := GetBytes(context.Background(), getter, []string{"addr1", "addr2"}, "key1", tt.opts...)
+			if !bytes.Equal(got, tt.want) {
+				t.Fatalf("GetBytes() = %x, want %x", got, tt.want)
+			}
+
+			if tt.wantErrIs == nil && err != nil || tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("GetBytes() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+
+	t.Run("результат не делит буфер с getter", func(t *testing.T) {
+		buf := bytes.Clone(payload)
+		getter := &MockTypedGetter[[]byte]{Responses: map[string]map[string]TypedResponse[[]byte]{
+			"addr1": {"key1": {Value: buf}},
+		}}
+
+		got, err := GetBytes(context.Background(), getter, []string{"addr1"}, "key1")
+		if err != nil {
+			t.Fatalf("GetBytes() error = %v", err)
+		}
+
+		clear(buf)
+		if !bytes.Equal(got, payload) {
+			t.Fatalf("GetBytes() = %x after the getter reused its buffer, want %x", got, payload)
+		}
+	})
+
+	t.Run("нет getter", func(t *testing.T) {
+		if _, err := GetBytes(context.Background(), nil, []string{"addr1"}, "key1"); !errors.Is(err, ErrNilGetter) {
+			t.Fatalf("GetBytes() error = %v, want %v", err, ErrNilGetter)
+		}
+	})
+}
+
+func TestGetBytesIgnoresSelector(t *testing.T) {
+	getter := &MockTypedGetter[[]byte]{Responses: map[string]map[string]TypedResponse[[]byte]{
+		"fast": {"key1": {Value: []byte("fast")}},
+		"slow": {"key1": {Value: []byte("slow"), Delay: time.Second}},
+	}}
+	last := func(candidates []AddressResult) (AddressResult, bool) {
+		return candidates[len(candidates)-1], true
+	}
+
+	start := time.Now()
+	got, err := GetBytes(context.Background(), getter, []string{"fast", "slow"}, "key1", WithSelector(last))
+	if err != nil || string(got) != "fast" {
+		t.Fatalf("GetBytes() = (%q, %v), want (%q, nil)", got, err, "fast")
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("GetBytes() took %v, want it not to wait for every address", elapsed)
+	}
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+)
+
+// minSweep is the number of entries below which an evicting cache never
+// sweeps.
+const minSweep = 64
+
+// Cache remembers the values resolved by GetCached for ttl. It is safe for
+// concurrent use. Failures are never cached. Expired values are kept for
+// GetWithStaleFallback, so a Cache holds every key it ever cached and grows
+// with their number.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	// evict drops expired values instead, for caches that never serve
+	// them stale. They are swept once the entries double since the last
+	// sweep, so the sweeps cost O(1) per store on average.
+	evict     bool
+	nextSweep int
+
+	mu      sync.Mutex
+	entries map[string]cachedValue
+}
+
+type cachedValue struct {
+	value   string
+	expires time.Time
+}
+
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cachedValue),
+	}
+}
+
+// GetCached returns the value cached for key if it has not expired yet, and
+// otherwise resolves it with Get and caches the result on success.
+func GetCached(ctx context.Context, cache *Cache, getter Getter, addresses []string, key string, opts ...Option) (string, error) {
+	if value, ok := cache.lookup(key); ok {
+		return value, nil
+	}
+
+	value, err := Get(ctx, getter, addresses, key, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	cache.store(key, value)
+	return value, nil
+}
+
+// GetWithStaleFallback works like GetCached, but when every address fails it
+// falls back to the value last cached for key, however old, reporting it as
+// stale with a nil error. Without such a value the error is returned, as it
+// is when ctx is done or the arguments are invalid.
+func GetWithStaleFallback(ctx context.Context, cache *Cache, getter Getter, addresses []string, key string, opts ...Option) (value string, stale bool, err error) {
+	value, err = GetCached(ctx, cache, getter, addresses, key, opts...)
+	if err == nil {
+		return value, false, nil
+	}
+
+	if ctx.Err() != nil || errors.Is(err, ErrNilGetter) || errors.Is(err, ErrEmptyKey) {
+		return "", false, err
+	}
+
+	if value, ok := cache.lookupStale(key); ok {
+		return value, true, nil
+	}
+
+	return "", false, err
+}
+
+func (c *Cache) lookup(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok || !c.now().Before(entry.expires) {
+		if ok && c.evict {
+			delete(c.entries, key)
+		}
+
+		return "", false
+	}
+
+	return entry.value, true
+}
+
+// lookupStale returns the value cached for key even if it has expired.
+func (c *Cache) lookupStale(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	return entry.value, ok
+}
+
+func (c *Cache) store(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	c.entries[key] = cachedValue{value: value, expires: now.Add(c.ttl)}
+	if c.evict && len(c.entries) >= c.nextSweep {
+		maps.DeleteFunc(c.entries, func(_ string, entry cachedValue) bool {
+			return !now.Before(entry.expires)
+		})
+		c.nextSweep = max(2*len(c.entries), minSweep)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetCached(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1"}, "bad": {Error: errors.New("connection error")}},
+	})
+	getter := newCountingGetter(mock)
+	addresses := []string{"addr1"}
+
+	now := time.Now()
+	cache := NewCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	tests := []struct {
+		name      string
+		advance   time.Duration
+		key       string
+		wantValue string
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "промах — запрос к адресу", key: "key1", wantValue: "value1", wantCalls: 1},
+		{name: "попадание в пределах TTL", advance: 30 * time.Second, key: "key1", wantValue: "value1", wantCalls: 1},
+		{name: "истечение TTL — повторный запрос", advance: 30 * time.Second, key: "key1", wantValue: "value1", wantCalls: 2},
+		{name: "ошибка не кэшируется", key: "bad", wantErr: true, wantCalls: 3},
+		{name: "ошибка запрашивается снова", key: "bad", wantErr: true, wantCalls: 4},
+	}
+
+	for _, tt := range tests {
+		now = now.Add(tt.advance)
+
+		got, err := GetCached(context.Background(), cache, getter, addresses, tt.key)
+
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: GetCached() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+
+		if got != tt.wantValue {
+			t.Fatalf("%s: GetCached() = %q, want %q", tt.name, got, tt.wantValue)
+		}
+
+		if n := getter.TotalCalls(); n != tt.wantCalls {
+			t.Fatalf("%s: calls = %d, want %d", tt.name, n, tt.wantCalls)
+		}
+	}
+}
+
+func TestGetCachedConcurrent(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1"}, "key2": {Value: "value2"}},
+	})
+	cache := NewCache(time.Minute)
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			key, want := "key1", "value1"
+			if i%2 == 1 {
+				key, want = "key2", "value2"
+			}
+
+			if got, err := GetCached(context.Background(), cache, mock, []string{"addr1"}, key); err != nil || got != want {
+				t.Errorf("GetCached(%q) = (%q, %v), want (%q, nil)", key, got, err, want)
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestGetWithStaleFallback(t *testing.T) {
+	live := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1"}},
+	})
+	down := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection error")}},
+	})
+
+	now := time.Now()
+	cache := NewCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	tests := []struct {
+		name      string
+		advance   time.Duration
+		getter    Getter
+		key       string
+		wantValue string
+		wantStale bool
+		wantErr   bool
+	}{
+		{name: "свежее значение кэшируется", getter: live, key: "key1", wantValue: "value1"},
+		{name: "в пределах TTL — из кэша", advance: 30 * time.Second, getter: down, key: "key1", wantValue: "value1"},
+		{name: "всё упало — устаревшее значение", advance: time.Hour, getter: down, key: "key1", wantValue: "value1", wantStale: true},
+		{name: "ни кэша, ни успеха", getter: down, key: "key2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		now = now.Add(tt.advance)
+
+		got, stale, err := GetWithStaleFallback(context.Background(), cache, tt.getter, []string{"addr1"}, tt.key)
+
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: GetWithStaleFallback() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+
+		if got != tt.wantValue || stale != tt.wantStale {
+			t.Fatalf("%s: GetWithStaleFallback() = (%q, %v), want (%q, %v)", tt.name, got, stale, tt.wantValue, tt.wantStale)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import "context"
+
+// Client is a Getter bundled with the options every Get through it uses, so
+// a service can configure it once at startup and share it. It is safe for
+// concurrent use as long as the stored options are, which is the case for
+// all the options of this package.
+type Client struct {
+	getter Getter
+	cfg    config
+}
+
+func NewClient(getter Getter, opts ...Option) *Client {
+	return &Client{getter: getter, cfg: newConfig(opts)}
+}
+
+// Get works like the Get function with the getter and options of c. opts
+// are applied on top of the stored options, for the one call.
+func (c *Client) Get(ctx context.Context, addresses []string, key string, opts ...Option) (string, error) {
+	cfg := c.cfg
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	value, _, err := race[string](ctx, c.getter, addresses, key, cfg)
+	return value, err
+}
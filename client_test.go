@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	responses := map[string]map[string]Response{
+		"fast": {"key1": {Value: "fast", Delay: 30 * time.Millisecond}},
+		"slow": {"key1": {Value: "slow", Delay: time.Second}},
+	}
+	broken := map[string]map[string]Response{}
+	for _, address := range []string{"addr1", "addr2", "addr3", "addr4"} {
+		broken[address] = map[string]Response{"key1": {Error: errors.New("connection error"), Delay: 10 * time.Millisecond}}
+	}
+
+	tests := []struct {
+		name            string
+		responses       map[string]map[string]Response
+		clientOpts      []Option
+		callOpts        []Option
+		addresses       []string
+		want            string
+		wantCalls       map[string]int
+		wantMaxInFlight int
+	}{
+		{
+			name:       "задержка хеджирования клиента соблюдается",
+			responses:  responses,
+			clientOpts: []Option{WithHedgeDelay(time.Hour)},
+			addresses:  []string{"fast", "slow"},
+			want:       "fast",
+			wantCalls:  map[string]int{"fast": 1, "slow": 0},
+		},
+		{
+			name:            "ограничение параллелизма клиента соблюдается",
+			responses:       broken,
+			clientOpts:      []Option{WithMaxConcurrency(2)},
+			addresses:       []string{"addr1", "addr2", "addr3", "addr4"},
+			wantCalls:       map[string]int{"addr1": 1, "addr2": 1, "addr3": 1, "addr4": 1},
+			wantMaxInFlight: 2,
+		},
+		{
+			name:       "опции вызова поверх опций клиента",
+			responses:  responses,
+			clientOpts: []Option{WithHedgeDelay(time.Hour)},
+			callOpts:   []Option{WithHedgeDelay(0)},
+			addresses:  []string{"fast", "slow"},
+			want:       "fast",
+			wantCalls:  map[string]int{"fast": 1, "slow": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(tt.responses))
+			client := NewClient(getter, tt.clientOpts...)
+
+			for range 2 {
+				got, err := client.Get(context.Background(), tt.addresses, "key1", tt.callOpts...)
+				if got != tt.want || (err == nil) != (tt.want != "") {
+					t.Fatalf("Client.Get() = (%q, %v), want %q", got, err, tt.want)
+				}
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != 2*want {
+					t.Fatalf("calls to %s over two Get calls = %d, want %d", address, n, 2*want)
+				}
+			}
+
+			if n := getter.MaxInFlight(); tt.wantMaxInFlight > 0 && n != tt.wantMaxInFlight {
+				t.Fatalf("max in flight = %d, want %d", n, tt.wantMaxInFlight)
+			}
+		})
+	}
+}
+
+func TestClientConcurrentCallOptions(t *testing.T) {
+	const callers = 8
+
+	errs := make([]error, callers)
+	responses := map[string]map[string]Response{"good": {"key1": {Value: "value", Delay: 20 * time.Millisecond}}}
+	for i := range errs {
+		errs[i] = fmt.Errorf("error %d", i)
+		responses[fmt.Sprintf("bad%d", i)] = map[string]Response{"key1": {Error: errs[i]}}
+	}
+
+	client := NewClient(NewMockGetter(responses),
+		WithFailFastOn(errors.New("a"), errors.New("b"), errors.New("c")), WithFailFastOn(errors.New("d")))
+
+	// Every caller makes its own error terminal and queries the address
+	// failing with the next caller's, which must stay non-terminal for it.
+	var wg sync.WaitGroup
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			addresses := []string{fmt.Sprintf("bad%d", (i+1)%callers), "good"}
+			for range 10 {
+				got, err := client.Get(context.Background(), addresses, "key1", WithFailFastOn(errs[i]))
+				if err != nil || got != "value" {
+					t.Errorf("Client.Get() = (%q, %v), want (%q, nil)", got, err, "value")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Clock is the source of time behind the timing of Get: hedge delays, soft
+// deadlines, start delays, backoff waits, per-attempt timeouts and the
+// latencies it measures. Tests can provide a fake one to drive that timing
+// by hand. Deadlines of the caller's ctx always follow the real clock, and
+// so do the ones derived from it: deadline budgets and the contexts set up
+// by GetWithTimeout, GetWithSoftDeadline and GetSimple. Breaker and Cache,
+// which outlive a single Get, and the Getter decorators keep to the real
+// clock too.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer is the Clock counterpart of *time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// WithClock makes Get take its time from clock. Without it, or with a nil
+// clock, the real clock is used.
+func WithClock(clock Clock) Option {
+	return func(c *config) {
+		c.clock = clock
+	}
+}
+
+// timeSource returns the configured clock, or the real one.
+func (c config) timeSource() Clock {
+	if c.clock != nil {
+		return c.clock
+	}
+
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (realClock) NewTimer(d time.Duration) Timer { return realTimer{time.NewTimer(d)} }
+
+type realTimer struct {
+	t *time.Timer
+}
+
+func (t realTimer) C() <-chan time.Time { return t.t.C }
+
+func (t realTimer) Stop() bool { return t.t.Stop() }
+
+func (t realTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+// withTimeout is context.WithTimeout on clock. Like a context deadline, a
+// timeout that expires makes the returned context fail with
+// context.DeadlineExceeded.
+func withTimeout(ctx context.Context, clock Clock, d time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := clock.(realClock); ok {
+		return context.WithTimeout(ctx, d)
+	}
+
+	inner, cancel := context.WithCancelCause(ctx)
+	tctx := &timeoutContext{Context: inner}
+	timer := clock.NewTimer(d)
+	go func() {
+		select {
+		case <-timer.C():
+			tctx.expired.Store(true)
+			cancel(context.DeadlineExceeded)
+		case <-inner.Done():
+		}
+	}()
+
+	return tctx, func() {
+		timer.Stop()
+		cancel(context.Canceled)
+	}
+}
+
+// timeoutContext reports the expiry of a withTimeout timer as
+// context.DeadlineExceeded, as a context deadline would.
+type timeoutContext struct {
+	context.Context
+	expired atomic.Bool
+}
+
+func (c *timeoutContext) Err() error {
+	if err := c.Context.Err(); err != nil && c.expired.Load() {
+		return context.DeadlineExceeded
+	}
+
+	return c.Context.Err()
+}
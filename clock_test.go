@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock only moves when Advance is called, firing the timers that came
+// due.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock  *fakeClock
+	c      chan time.Time
+	when   time.Time
+	active bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1), when: c.now.Add(d), active: true}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	for _, t := range c.timers {
+		if t.active && !t.when.After(c.now) {
+			t.active = false
+			select {
+			case t.c <- c.now:
+			default:
+			}
+		}
+	}
+}
+
+func (t *fakeTimer) C() <-chan time.Time { return t.c }
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.active
+	t.active = false
+	return active
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+
+	active := t.active
+	t.active, t.when = true, t.clock.now.Add(d)
+	return active
+}
+
+// announcingGetter reports every call on calls. Addresses listed in hang
+// never answer and wait for ctx instead; the others succeed at once.
+type announcingGetter struct {
+	calls chan string
+	hang  map[string]bool
+}
+
+func (g announcingGetter) Get(ctx context.Context, address, key string) (string, error) {
+	g.calls <- address
+	if g.hang[address] {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+
+	return "value-" + address, nil
+}
+
+func TestGetWithClock(t *testing.T) {
+	receive := func(t *testing.T, c <-chan string) string {
+		t.Helper()
+
+		select {
+		case v := <-c:
+			return v
+		case <-time.After(time.Second):
+			t.Fatal("no call within a second")
+			return ""
+		}
+	}
+
+	t.Run("хедж по ручному времени", func(t *testing.T) {
+		clock := newFakeClock()
+		getter := announcingGetter{calls: make(chan string, 2), hang: map[string]bool{"primary": true}}
+
+		type outcome struct {
+			value string
+			err   error
+		}
+		done := make(chan outcome, 1)
+		go func() {
+			value, err := Get(context.Background(), getter, []string{"primary", "secondary"}, "key1",
+				WithHedgeDelay(time.Hour), WithClock(clock))
+			done <- outcome{value, err}
+		}()
+
+		if got := receive(t, getter.calls); got != "primary" {
+			t.Fatalf("first call to %s, want primary", got)
+		}
+
+		clock.Advance(59 * time.Minute)
+		select {
+		case got := <-getter.calls:
+			t.Fatalf("call to %s before the hedge delay passed", got)
+		default:
+		}
+
+		clock.Advance(time.Minute)
+		if got := receive(t, getter.calls); got != "secondary" {
+			t.Fatalf("hedged call to %s, want secondary", got)
+		}
+
+		if got := <-done; got.err != nil || got.value != "value-secondary" {
+			t.Fatalf("Get() = (%q, %v), want (%q, nil)", got.value, got.err, "value-secondary")
+		}
+	})
+
+	t.Run("таймаут попытки по ручному времени", func(t *testing.T) {
+		clock := newFakeClock()
+		getter := announcingGetter{calls: make(chan string, 1), hang: map[string]bool{"primary": true}}
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := Get(context.Background(), getter, []string{"primary"}, "key1",
+				WithAttemptTimeout(time.Minute), WithClock(clock))
+			done <- err
+		}()
+
+		receive(t, getter.calls)
+		clock.Advance(time.Minute)
+
+		if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Get() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("окно свежести по ручному времени", func(t *testing.T) {
+		clock := newFakeClock()
+		getter := announcingGetter{calls: make(chan string, 2), hang: map[string]bool{"fresh": true}}
+
+		type outcome struct {
+			value string
+			err   error
+		}
+		done := make(chan outcome, 1)
+		go func() {
+			value, err := GetFreshest(context.Background(), getter, []string{"fresh", "stale"}, "key1", time.Hour, WithClock(clock))
+			done <- outcome{value, err}
+		}()
+
+		receive(t, getter.calls)
+		receive(t, getter.calls)
+		select {
+		case got := <-done:
+			t.Fatalf("GetFreshest() = (%q, %v) before the grace window passed", got.value, got.err)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		// The grace timer is only created once the stale answer is seen,
+		// so the clock is advanced until it fires.
+		deadline := time.After(time.Second)
+		for {
+			clock.Advance(time.Hour)
+			select {
+			case got := <-done:
+				if got.err != nil || got.value != "value-stale" {
+					t.Fatalf("GetFreshest() = (%q, %v), want (%q, nil)", got.value, got.err, "value-stale")
+				}
+				return
+			case <-deadline:
+				t.Fatal("GetFreshest() did not return after the grace window")
+			case <-time.After(5 * time.Millisecond):
+			}
+		}
+	})
+}
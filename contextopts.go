@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// Options are per-request overrides of the options passed to Get, attached
+// to a context with WithContextOptions, e.g. by a middleware. A zero field
+// leaves the corresponding option as the call site set it.
+type Options struct {
+	MaxConcurrency int
+	HedgeDelay     time.Duration
+	AttemptTimeout time.Duration
+	MaxAttempts    int
+}
+
+type contextOptionsKey struct{}
+
+// WithContextOptions returns a copy of ctx carrying opts. Get calls made with
+// it apply the non-zero fields of opts on top of their own options.
+func WithContextOptions(ctx context.Context, opts Options) context.Context {
+	return context.WithValue(ctx, contextOptionsKey{}, opts)
+}
+
+// withContext returns c overridden by the Options attached to ctx, if any.
+func (c config) withContext(ctx context.Context) config {
+	opts, ok := ctx.Value(contextOptionsKey{}).(Options)
+	if !ok {
+		return c
+	}
+
+	if opts.MaxConcurrency != 0 {
+		c.maxConcurrency = opts.MaxConcurrency
+	}
+
+	if opts.HedgeDelay != 0 {
+		c.hedgeDelay = opts.HedgeDelay
+	}
+
+	if opts.AttemptTimeout != 0 {
+		c.attemptTimeout = opts.AttemptTimeout
+	}
+
+	if opts.MaxAttempts != 0 {
+		c.maxAttempts = opts.MaxAttempts
+	}
+
+	return c
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithContextOptions(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1", Delay: 50 * time.Millisecond}},
+		"addr2": {"key1": {Value: "value2", Delay: 10 * time.Millisecond}},
+	})
+	opts := []Option{WithHedgeDelay(time.Second)}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantValue string
+		wantCalls map[string]int
+	}{
+		{
+			name:      "без переопределения — ждём первый адрес",
+			ctx:       context.Background(),
+			wantValue: "value1",
+			wantCalls: map[string]int{"addr1": 1, "addr2": 0},
+		},
+		{
+			name:      "контекст укорачивает задержку хеджирования",
+			ctx:       WithContextOptions(context.Background(), Options{HedgeDelay: 5 * time.Millisecond}),
+			wantValue: "value2",
+			wantCalls: map[string]int{"addr1": 1, "addr2": 1},
+		},
+		{
+			name:      "нулевые поля не переопределяют",
+			ctx:       WithContextOptions(context.Background(), Options{MaxAttempts: 2}),
+			wantValue: "value1",
+			wantCalls: map[string]int{"addr1": 1, "addr2": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(mock)
+
+			got, err := Get(tt.ctx, getter, []string{"addr1", "addr2"}, "key1", opts...)
+			if err != nil || got != tt.wantValue {
+				t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, tt.wantValue)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Chain wraps base in middlewares, such as the decorators below, so that
+// the first middleware is the outermost one: Chain(base, a, b) is
+// a(b(base)), and a call goes through a, then b, then reaches base.
+func Chain(base Getter, middlewares ...func(Getter) Getter) Getter {
+	getter := base
+	for _, middleware := range slices.Backward(middlewares) {
+		getter = middleware(getter)
+	}
+
+	return getter
+}
+
+// RetryGetter is a Getter that calls Getter again, up to MaxAttempts times
+// in total, until a call succeeds. Before the n-th retry it waits
+// Backoff * 2^(n-1), capped at MaxBackoff when that is positive, and gives
+// up early once ctx is done. A MaxAttempts of 1 or less makes a single call.
+// Unlike WithRetry it applies to every use of the getter, Get or not.
+type RetryGetter struct {
+	Getter      Getter
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (r RetryGetter) Get(ctx context.Context, address, key string) (value string, err error) {
+	cfg := config{backoffBase: r.Backoff, backoffMax: r.MaxBackoff}
+	for attempt := range max(r.MaxAttempts, 1) {
+		if attempt > 0 {
+			if err := sleep(ctx, realClock{}, cfg.backoff(attempt)); err != nil {
+				return "", err
+			}
+		}
+
+		value, err = r.Getter.Get(ctx, address, key)
+		if err == nil || ctx.Err() != nil {
+			return value, err
+		}
+	}
+
+	return value, err
+}
+
+// CacheGetter is a Getter that remembers the successful values of the
+// getter it wraps for ttl, separately for every address and key, so it can
+// sit under Get across many addresses. Failures are never cached, and
+// expired values are dropped, so its memory follows the keys in use. It is
+// safe for concurrent use.
+type CacheGetter struct {
+	getter Getter
+	cache  *Cache
+}
+
+func NewCacheGetter(getter Getter, ttl time.Duration) *CacheGetter {
+	cache := NewCache(ttl)
+	cache.evict = true
+	return &CacheGetter{getter: getter, cache: cache}
+}
+
+func (c *CacheGetter) Get(ctx context.Context, address, key string) (string, error) {
+	// The NUL byte cannot be confused with a part of an address.
+	entry := address + "\x00" + key
+	if value, ok := c.cache.lookup(entry); ok {
+		return value, nil
+	}
+
+	value, err := c.getter.Get(ctx, address, key)
+	if err != nil {
+		return "", err
+	}
+
+	c.cache.store(entry, value)
+	return value, nil
+}
+
+// TimeoutGetter is a Getter that bounds every call to Getter by Timeout,
+// like WithAttemptTimeout does for the calls made by Get. A call cut off by
+// it fails with an error matching context.DeadlineExceeded, even when
+// Getter reports the cut-off differently. A Timeout of zero or less leaves
+// the calls unbounded.
+type TimeoutGetter struct {
+	Getter  Getter
+	Timeout time.Duration
+}
+
+func (t TimeoutGetter) Get(ctx context.Context, address, key string) (string, error) {
+	if t.Timeout <= 0 {
+		return t.Getter.Get(ctx, address, key)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+
+	value, err := t.Getter.Get(callCtx, address, key)
+	if err != nil && callCtx.Err() != nil && ctx.Err() == nil && !errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("%w after %v: %w", context.DeadlineExceeded, t.Timeout, err)
+	}
+
+	return value, err
+}
+
+// ChainGetter is a Getter that tries its getters in order for the same
+// address and key, returning the first success, such as a fast cache in
+// front of a slow authoritative store. Every getter is consulted only after
+// the previous one failed; once all of them have, the error joins their
+// failures. It stops early once ctx is done.
+type ChainGetter []Getter
+
+func (c ChainGetter) Get(ctx context.Context, address, key string) (string, error) {
+	if len(c) == 0 {
+		return "", fmt.Errorf("empty chain: %w", ErrNilGetter)
+	}
+
+	errs := make([]error, 0, len(c))
+	for _, getter := range c {
+		value, err := getter.Get(ctx, address, key)
+		if err == nil {
+			return value, nil
+		}
+
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return "", errors.Join(errs...)
+}
+
+// Recorder receives the outcome of every call made through a MetricsGetter:
+// err is nil for a success. It may be called concurrently.
+type Recorder interface {
+	RecordCall(address string, err error, latency time.Duration)
+}
+
+// MetricsGetter is a Getter that reports every call to Getter, with its
+// latency, to Recorder. Unlike WithMetrics it also sees the calls made
+// outside of Get.
+type MetricsGetter struct {
+	Getter   Getter
+	Recorder Recorder
+}
+
+func (m MetricsGetter) Get(ctx context.Context, address, key string) (string, error) {
+	start := time.Now()
+	value, err := m.Getter.Get(ctx, address, key)
+	m.Recorder.RecordCall(address, err, time.Since(start))
+	return value, err
+}
@@ -0,0 +1,431 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRetryGetter(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    map[string]int
+		maxAttempts int
+		backoff     time.Duration
+		ttl         time.Duration
+		wantValue   string
+		wantErrIs   error
+		wantErr     bool
+		wantCalls   int
+	}{
+		{
+			name:        "успех после двух неудач",
+			failures:    map[string]int{"addr1": 2},
+			maxAttempts: 3,
+			ttl:         time.Second,
+			wantValue:   "value-addr1",
+			wantCalls:   3,
+		},
+		{
+			name:        "попытки исчерпаны",
+			failures:    map[string]int{"addr1": -1},
+			maxAttempts: 3,
+			ttl:         time.Second,
+			wantErr:     true,
+			wantCalls:   3,
+		},
+		{
+			name:        "без повторов",
+			failures:    map[string]int{"addr1": -1},
+			maxAttempts: 0,
+			ttl:         time.Second,
+			wantErr:     true,
+			wantCalls:   1,
+		},
+		{
+			name:        "отмена контекста во время ожидания",
+			failures:    map[string]int{"addr1": -1},
+			maxAttempts: 10,
+			backoff:     40 * time.Millisecond,
+			ttl:         60 * time.Millisecond,
+			wantErr:     true,
+			wantErrIs:   context.DeadlineExceeded,
+			wantCalls:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := newFlakyGetter(tt.failures, 0)
+			getter := RetryGetter{Getter: flaky, MaxAttempts: tt.maxAttempts, Backoff: tt.backoff}
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ttl)
+			defer cancel()
+
+			got, err := Get(ctx, getter, []string{"addr1"}, "key1")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("Get() error = %v, want errors.Is(err, %v) == true", err, tt.wantErrIs)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("Get() = %q, want %q", got, tt.wantValue)
+			}
+
+			if n := flaky.Calls("addr1"); n != tt.wantCalls {
+				t.Fatalf("calls to addr1 = %d, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestCacheGetter(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1"}, "bad": {Error: errors.New("connection error")}},
+		"addr2": {"key1": {Value: "value2"}},
+	})
+	counting := newCountingGetter(mock)
+
+	now := time.Now()
+	getter := NewCacheGetter(counting, time.Minute)
+	getter.cache.now = func() time.Time { return now }
+
+	tests := []struct {
+		name      string
+		advance   time.Duration
+		address   string
+		key       string
+		wantValue string
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "промах", address: "addr1", key: "key1", wantValue: "value1", wantCalls: 1},
+		{name: "попадание", advance: 30 * time.Second, address: "addr1", key: "key1", wantValue: "value1", wantCalls: 1},
+		{name: "другой адрес — отдельная запись", address: "addr2", key: "key1", wantValue: "value2", wantCalls: 2},
+		{name: "истечение TTL", advance: 30 * time.Second, address: "addr1", key: "key1", wantValue: "value1", wantCalls: 3},
+		{name: "ошибка не кэшируется", address: "addr1", key: "bad", wantErr: true, wantCalls: 4},
+		{name: "ошибка запрашивается снова", address: "addr1", key: "bad", wantErr: true, wantCalls: 5},
+	}
+
+	for _, tt := range tests {
+		now = now.Add(tt.advance)
+
+		got, err := getter.Get(context.Background(), tt.address, tt.key)
+
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("%s: Get() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+
+		if got != tt.wantValue {
+			t.Fatalf("%s: Get() = %q, want %q", tt.name, got, tt.wantValue)
+		}
+
+		if n := counting.TotalCalls(); n != tt.wantCalls {
+			t.Fatalf("%s: calls = %d, want %d", tt.name, n, tt.wantCalls)
+		}
+	}
+}
+
+func TestCacheGetterEvictsExpired(t *testing.T) {
+	const keys = 1000
+
+	responses := map[string]map[string]Response{"addr1": {}}
+	for i := range keys {
+		responses["addr1"][fmt.Sprintf("key%d", i)] = Response{Value: "value"}
+	}
+
+	now := time.Now()
+	getter := NewCacheGetter(NewMockGetter(responses), time.Minute)
+	getter.cache.now = func() time.Time { return now }
+
+	// Every key is cached once and never asked for again, so only the
+	// sweeps can drop them.
+	for i := range keys {
+		now = now.Add(time.Second)
+		if _, err := getter.Get(context.Background(), "addr1", fmt.Sprintf("key%d", i)); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	if n := len(getter.cache.entries); n > 2*60+minSweep {
+		t.Fatalf("cache holds %d entries, want about the 60 from the last minute", n)
+	}
+
+	t.Run("истёкшая запись удаляется при чтении", func(t *testing.T) {
+		now = now.Add(time.Hour)
+		if _, ok := getter.cache.lookup("addr1\x00key999"); ok {
+			t.Fatal("lookup() found an expired entry")
+		}
+
+		if _, ok := getter.cache.entries["addr1\x00key999"]; ok {
+			t.Fatal("expired entry kept after lookup")
+		}
+	})
+}
+
+func TestCacheGetterConcurrent(t *testing.T) {
+	responses := map[string]map[string]Response{}
+	for i := range 4 {
+		responses[fmt.Sprintf("addr%d", i)] = map[string]Response{"key1": {Value: fmt.Sprintf("value%d", i)}}
+	}
+	getter := NewCacheGetter(NewMockGetter(responses), time.Minute)
+
+	var wg sync.WaitGroup
+	for i := range 32 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			address := fmt.Sprintf("addr%d", i%4)
+			got, err := Get(context.Background(), getter, []string{address}, "key1")
+			if want := fmt.Sprintf("value%d", i%4); err != nil || got != want {
+				t.Errorf("Get(%s) = (%q, %v), want (%q, nil)", address, got, err, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+type ignoringCancelGetter struct{}
+
+func (g ignoringCancelGetter) Get(ctx context.Context, address, key string) (string, error) {
+	<-ctx.Done()
+	return "", errors.New("request aborted")
+}
+
+func TestTimeoutGetter(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"slow": {"key1": {Value: "slow", Delay: time.Second}},
+		"fast": {"key1": {Value: "fast", Delay: 5 * time.Millisecond}},
+	})
+
+	tests := []struct {
+		name      string
+		getter    Getter
+		address   string
+		timeout   time.Duration
+		wantValue string
+		wantErrIs error
+	}{
+		{name: "медленный обрывается по таймауту", getter: mock, address: "slow", timeout: 30 * time.Millisecond, wantErrIs: context.DeadlineExceeded},
+		{name: "быстрый проходит", getter: mock, address: "fast", timeout: 30 * time.Millisecond, wantValue: "fast"},
+		{name: "без таймаута", getter: mock, address: "fast", wantValue: "fast"},
+		{name: "своя ошибка оборачивается", getter: ignoringCancelGetter{}, address: "slow", timeout: 30 * time.Millisecond, wantErrIs: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := TimeoutGetter{Getter: tt.getter, Timeout: tt.timeout}
+
+			start := time.Now()
+			got, err := getter.Get(context.Background(), tt.address, "key1")
+			elapsed := time.Since(start)
+
+			if got != tt.wantValue || !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("Get() = (%q, %v), want (%q, %v)", got, err, tt.wantValue, tt.wantErrIs)
+			}
+
+			if elapsed > 200*time.Millisecond {
+				t.Fatalf("Get() took %v, want it cut off at the timeout", elapsed)
+			}
+		})
+	}
+}
+
+type addressStats struct {
+	calls, errors int
+	latency       time.Duration
+}
+
+type recordingRecorder struct {
+	mu    sync.Mutex
+	stats map[string]addressStats
+}
+
+func (r *recordingRecorder) RecordCall(address string, err error, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.stats[address]
+	s.calls++
+	if err != nil {
+		s.errors++
+	}
+	s.latency += latency
+	r.stats[address] = s
+}
+
+func TestMetricsGetter(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection error"), Delay: 10 * time.Millisecond}},
+		"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+	})
+	recorder := &recordingRecorder{stats: map[string]addressStats{}}
+	getter := MetricsGetter{Getter: mock, Recorder: recorder}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for range 2 {
+		if got, err := Get(ctx, getter, []string{"addr1", "addr2"}, "key1"); err != nil || got != "value2" {
+			t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value2")
+		}
+	}
+
+	if _, err := getter.Get(ctx, "addr1", "key1"); err == nil {
+		t.Fatal("Get() error = nil, want addr1's failure")
+	}
+
+	want := map[string]addressStats{
+		"addr1": {calls: 3, errors: 3, latency: 30 * time.Millisecond},
+		"addr2": {calls: 2, errors: 0, latency: 40 * time.Millisecond},
+	}
+	for address, w := range want {
+		got := recorder.stats[address]
+		if got.calls != w.calls || got.errors != w.errors || got.latency < w.latency {
+			t.Fatalf("stats of %s = %+v, want %d calls, %d errors and a latency of at least %v", address, got, w.calls, w.errors, w.latency)
+		}
+	}
+}
+
+func TestChainGetter(t *testing.T) {
+	errMiss := errors.New("cache miss")
+	errDown := errors.New("store unavailable")
+	cache := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "cached1"}, "key2": {Error: errMiss}, "key3": {Error: errMiss}},
+		"addr2": {"key1": {Error: errMiss}},
+	})
+	store := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "stored1"}, "key2": {Value: "stored2"}, "key3": {Error: errDown}},
+		"addr2": {"key1": {Value: "stored-addr2"}},
+	})
+
+	tests := []struct {
+		name           string
+		address        string
+		key            string
+		want           string
+		wantErrIs      []error
+		wantStoreCalls int
+	}{
+		{name: "попадание в кэш", address: "addr1", key: "key1", want: "cached1"},
+		{name: "промах идёт в хранилище", address: "addr1", key: "key2", want: "stored2", wantStoreCalls: 1},
+		{name: "промах по другому адресу", address: "addr2", key: "key1", want: "stored-addr2", wantStoreCalls: 1},
+		{name: "все слои отказали", address: "addr1", key: "key3", wantErrIs: []error{errMiss, errDown}, wantStoreCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counted := newCountingGetter(store)
+			getter := ChainGetter{cache, counted}
+
+			got, err := getter.Get(context.Background(), tt.address, tt.key)
+			if got != tt.want {
+				t.Fatalf("Get() = %q, want %q", got, tt.want)
+			}
+
+			if len(tt.wantErrIs) == 0 && err != nil {
+				t.Fatalf("Get() error = %v, want nil", err)
+			}
+
+			for _, want := range tt.wantErrIs {
+				if !errors.Is(err, want) {
+					t.Fatalf("Get() error = %v, want errors.Is(err, %v) == true", err, want)
+				}
+			}
+
+			if n := counted.Calls(tt.address); n != tt.wantStoreCalls {
+				t.Fatalf("store calls = %d, want %d", n, tt.wantStoreCalls)
+			}
+		})
+	}
+
+	t.Run("слои под Get", func(t *testing.T) {
+		got, err := Get(context.Background(), ChainGetter{cache, store}, []string{"addr2"}, "key1")
+		if err != nil || got != "stored-addr2" {
+			t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "stored-addr2")
+		}
+	})
+
+	t.Run("пустая цепочка", func(t *testing.T) {
+		if _, err := (ChainGetter{}).Get(context.Background(), "addr1", "key1"); !errors.Is(err, ErrNilGetter) {
+			t.Fatalf("Get() error = %v, want %v", err, ErrNilGetter)
+		}
+	})
+}
+
+// tracingGetter logs its name to log on the way in and out of every call.
+type tracingGetter struct {
+	Getter
+
+	name string
+	log  *[]string
+}
+
+func (g tracingGetter) Get(ctx context.Context, address, key string) (string, error) {
+	*g.log = append(*g.log, g.name+" in")
+	defer func() { *g.log = append(*g.log, g.name+" out") }()
+
+	return g.Getter.Get(ctx, address, key)
+}
+
+func TestChain(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1"}},
+	})
+
+	var log []string
+	middleware := func(name string) func(Getter) Getter {
+		return func(next Getter) Getter {
+			return tracingGetter{Getter: next, name: name, log: &log}
+		}
+	}
+
+	tests := []struct {
+		name        string
+		middlewares []func(Getter) Getter
+		wantLog     []string
+	}{
+		{name: "без промежуточных слоёв", wantLog: nil},
+		{
+			name:        "внешний слой первым",
+			middlewares: []func(Getter) Getter{middleware("outer"), middleware("inner")},
+			wantLog:     []string{"outer in", "inner in", "inner out", "outer out"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log = nil
+
+			got, err := Chain(mock, tt.middlewares...).Get(context.Background(), "addr1", "key1")
+			if err != nil || got != "value1" {
+				t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value1")
+			}
+
+			if !slices.Equal(log, tt.wantLog) {
+				t.Fatalf("calls went %v, want %v", log, tt.wantLog)
+			}
+		})
+	}
+
+	t.Run("с настоящими декораторами", func(t *testing.T) {
+		flaky := newFlakyGetter(map[string]int{"addr1": 1}, 0)
+		getter := Chain(flaky,
+			func(next Getter) Getter { return RetryGetter{Getter: next, MaxAttempts: 2} },
+			func(next Getter) Getter { return TimeoutGetter{Getter: next, Timeout: time.Second} },
+		)
+
+		if got, err := Get(context.Background(), getter, []string{"addr1"}, "key1"); err != nil || got != "value-addr1" {
+			t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value-addr1")
+		}
+	})
+}
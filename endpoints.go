@@ -0,0 +1,85 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+// Endpoint is an address together with metadata about it that steers how
+// GetEndpoints queries it. The zero metadata puts every endpoint on an equal
+// footing, so GetEndpoints over bare addresses is the same as Get.
+type Endpoint struct {
+	Address string
+
+	// Weight ranks the endpoints of a tier as in GetWeighted: heavier ones
+	// are started first.
+	Weight int
+
+	// Tier groups the endpoints as in GetTiered: the endpoints of a tier
+	// are only started once every endpoint of all lower tiers failed.
+	Tier int
+
+	// Region is where the endpoint runs, for WithPreferredRegion.
+	Region string
+}
+
+// WithPreferredRegion makes GetEndpoints query the endpoints of region
+// before the others of the same tier: the endpoints in other regions are
+// only started once every endpoint in region failed. It has no effect on
+// endpoints without a region, and so on the Get variants over bare
+// addresses.
+func WithPreferredRegion(region string) Option {
+	return func(c *config) {
+		c.region = region
+	}
+}
+
+// GetEndpoints races endpoints like Get, according to their metadata. Get
+// over a list of addresses is GetEndpoints over endpoints without metadata:
+// it races them as the single tier those endpoints make up, on the same
+// path, without building the endpoints.
+func GetEndpoints(ctx context.Context, getter Getter, endpoints []Endpoint, key string, opts ...Option) (string, error) {
+	cfg := newConfig(opts)
+	value, _, err := raceTiers(ctx, getter, endpointTiers(endpoints, cfg.region, cfg.stagger()), key, cfg)
+	return value, err
+}
+
+// endpointTiers groups endpoints by ascending tier, each ordered by weight.
+// When region is set, every tier is split in two: the endpoints in region
+// first, then the others.
+func endpointTiers(endpoints []Endpoint, region string, stagger time.Duration) []tier {
+	group := func(e Endpoint) [2]int {
+		remote := 0
+		if region != "" && e.Region != region {
+			remote = 1
+		}
+
+		return [2]int{e.Tier, remote}
+	}
+
+	sorted := slices.Clone(endpoints)
+	slices.SortStableFunc(sorted, func(a, b Endpoint) int {
+		ga, gb := group(a), group(b)
+		return cmp.Or(cmp.Compare(ga[0], gb[0]), cmp.Compare(ga[1], gb[1]))
+	})
+
+	var tiers []tier
+	for start := 0; start < len(sorted); {
+		end := start + 1
+		for end < len(sorted) && group(sorted[end]) == group(sorted[start]) {
+			end++
+		}
+
+		weighted := make([]WeightedAddress, 0, end-start)
+		for _, e := range sorted[start:end] {
+			weighted = append(weighted, WeightedAddress{Address: e.Address, Weight: e.Weight})
+		}
+
+		tiers = append(tiers, weightedTier(weighted, stagger))
+		start = end
+	}
+
+	return tiers
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetEndpoints(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"light":  {"key1": {Error: errors.New("connection error"), Delay: 10 * time.Millisecond}},
+		"heavy":  {"key1": {Error: errors.New("connection error"), Delay: 10 * time.Millisecond}},
+		"backup": {"key1": {Value: "backup"}},
+		"fast":   {"key1": {Value: "fast"}},
+	})
+
+	tests := []struct {
+		name      string
+		endpoints []Endpoint
+		wantValue string
+		wantOrder []string
+	}{
+		{
+			name: "старший ярус раньше, тяжёлый вес раньше",
+			endpoints: []Endpoint{
+				{Address: "backup", Tier: 1},
+				{Address: "light", Weight: 1},
+				{Address: "heavy", Weight: 5},
+			},
+			wantValue: "backup",
+			wantOrder: []string{"heavy", "light", "backup"},
+		},
+		{
+			name: "успех старшего яруса — младший не запрашивается",
+			endpoints: []Endpoint{
+				{Address: "backup", Tier: 1},
+				{Address: "fast"},
+			},
+			wantValue: "fast",
+			wantOrder: []string{"fast"},
+		},
+		{
+			name:      "без метаданных — как Get",
+			endpoints: []Endpoint{{Address: "light"}, {Address: "backup"}},
+			wantValue: "backup",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := &orderRecordingGetter{Getter: mock}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetEndpoints(ctx, getter, tt.endpoints, "key1")
+			if err != nil || got != tt.wantValue {
+				t.Fatalf("GetEndpoints() = (%q, %v), want (%q, nil)", got, err, tt.wantValue)
+			}
+
+			if tt.wantOrder != nil && !slices.Equal(getter.order, tt.wantOrder) {
+				t.Fatalf("queried %v, want %v", getter.order, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestGetEndpointsWithPreferredRegion(t *testing.T) {
+	endpoints := []Endpoint{
+		{Address: "us-1", Region: "us"},
+		{Address: "eu-1", Region: "eu"},
+		{Address: "eu-2", Region: "eu"},
+	}
+
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		opts      []Option
+		wantValue string
+		wantCalls map[string]int
+	}{
+		{
+			name: "свой регион отвечает — чужой не запускается",
+			responses: map[string]map[string]Response{
+				"us-1": {"key1": {Value: "us", Delay: 10 * time.Millisecond}},
+				"eu-1": {"key1": {Value: "eu"}},
+				"eu-2": {"key1": {Value: "eu"}},
+			},
+			opts:      []Option{WithPreferredRegion("eu")},
+			wantValue: "eu",
+			wantCalls: map[string]int{"us-1": 0},
+		},
+		{
+			name: "свой регион упал — переход к чужому",
+			responses: map[string]map[string]Response{
+				"us-1": {"key1": {Value: "us"}},
+				"eu-1": {"key1": {Error: errors.New("connection error")}},
+				"eu-2": {"key1": {Error: errors.New("connection error")}},
+			},
+			opts:      []Option{WithPreferredRegion("eu")},
+			wantValue: "us",
+			wantCalls: map[string]int{"us-1": 1, "eu-1": 1, "eu-2": 1},
+		},
+		{
+			name: "без предпочтения — гонка всех регионов",
+			responses: map[string]map[string]Response{
+				"us-1": {"key1": {Value: "us"}},
+				"eu-1": {"key1": {Value: "eu", Delay: 50 * time.Millisecond}},
+				"eu-2": {"key1": {Value: "eu", Delay: 50 * time.Millisecond}},
+			},
+			wantValue: "us",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(tt.responses))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetEndpoints(ctx, getter, endpoints, "key1", tt.opts...)
+			if err != nil || got != tt.wantValue {
+				t.Fatalf("GetEndpoints() = (%q, %v), want (%q, nil)", got, err, tt.wantValue)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetIsGetEndpointsWithoutMetadata(t *testing.T) {
+	errConn := errors.New("connection error")
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		addresses []string
+		opts      []Option
+		racy      bool
+	}{
+		{
+			name: "первый успех",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errConn}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			racy:      true,
+		},
+		{
+			name: "все падают",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errConn}},
+				"addr2": {"key1": {Error: errors.New("timeout")}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			opts:      []Option{WithSequential()},
+		},
+		{
+			name:      "ключа нет нигде",
+			responses: map[string]map[string]Response{"addr1": {}, "addr2": {}},
+			addresses: []string{"addr1", "addr2"},
+		},
+		{
+			name:      "пустой список",
+			responses: map[string]map[string]Response{},
+		},
+		{
+			name: "последовательно с дедупликацией",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errConn}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			addresses: []string{"addr1", "addr1", "addr2"},
+			opts:      []Option{WithSequential(), WithDedup()},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := make([]Endpoint, len(tt.addresses))
+			for i, address := range tt.addresses {
+				endpoints[i] = Endpoint{Address: address}
+			}
+
+			plain := &orderRecordingGetter{Getter: NewMockGetter(tt.responses)}
+			want, wantErr := Get(context.Background(), plain, tt.addresses, "key1", tt.opts...)
+
+			viaEndpoints := &orderRecordingGetter{Getter: NewMockGetter(tt.responses)}
+			got, err := GetEndpoints(context.Background(), viaEndpoints, endpoints, "key1", tt.opts...)
+
+			if got != want || fmt.Sprint(err) != fmt.Sprint(wantErr) {
+				t.Fatalf("GetEndpoints() = (%q, %v), Get() = (%q, %v)", got, err, want, wantErr)
+			}
+
+			// The losers of a race may not have been called yet.
+			if tt.racy {
+				return
+			}
+
+			slices.Sort(plain.order)
+			slices.Sort(viaEndpoints.order)
+			if !slices.Equal(plain.order, viaEndpoints.order) {
+				t.Fatalf("GetEndpoints() queried %v, Get() queried %v", viaEndpoints.order, plain.order)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrAllFailed is matched by every MultiError, that is whenever every
+// queried address failed. A done ctx and ErrKeyNotFound are reported on
+// their own and do not match it.
+var ErrAllFailed = errors.New("all addresses failed")
+
+// AddressError is the failure of a single address.
+type AddressError struct {
+	Address string
+	Err     error
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Address, e.Err)
+}
+
+func (e *AddressError) Unwrap() error {
+	return e.Err
+}
+
+// PanicError is the failure of an address whose getter panicked. Value is
+// what the panic was called with.
+type PanicError struct {
+	Value any
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("getter panicked: %v", e.Value)
+}
+
+// Unwrap returns Value when the getter panicked with an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// MultiError is returned when every address failed. Errors holds one entry
+// per queried address, in input order; errors.Is and errors.As look through
+// all of them, and errors.Is also matches ErrAllFailed.
+type MultiError struct {
+	Errors []AddressError
+}
+
+func (e *MultiError) Error() string {
+	var b strings.Builder
+	for i := range e.Errors {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		b.WriteString(e.Errors[i].Error())
+	}
+
+	return b.String()
+}
+
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i := range e.Errors {
+		errs[i] = &e.Errors[i]
+	}
+
+	return errs
+}
+
+func (e *MultiError) Is(target error) bool {
+	return target == ErrAllFailed
+}
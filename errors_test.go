@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMultiError(t *testing.T) {
+	errConn := errors.New("connection error")
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errConn, Delay: 20 * time.Millisecond}},
+		"addr2": {"key1": {Value: "value2", Delay: time.Second}},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := Get(ctx, mock, []string{"addr1", "addr2", "addr3"}, "key1", WithAttemptTimeout(30*time.Millisecond))
+
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("Get() error = %v (%T), want *MultiError", err, err)
+	}
+
+	wantAddresses := []string{"addr1", "addr2", "addr3"}
+	if len(multiErr.Errors) != len(wantAddresses) {
+		t.Fatalf("MultiError has %d entries, want %d", len(multiErr.Errors), len(wantAddresses))
+	}
+
+	for i, e := range multiErr.Errors {
+		if e.Address != wantAddresses[i] || e.Err == nil {
+			t.Fatalf("MultiError.Errors[%d] = %+v, want a failure of %s", i, e, wantAddresses[i])
+		}
+	}
+
+	for _, want := range []error{errConn, context.DeadlineExceeded} {
+		if !errors.Is(err, want) {
+			t.Fatalf("Get() error = %v, want errors.Is(err, %v) == true", err, want)
+		}
+	}
+
+	var addrErr *AddressError
+	if !errors.As(err, &addrErr) || addrErr.Address != "addr1" {
+		t.Fatalf("errors.As(err, *AddressError) = %+v, want the error of addr1", addrErr)
+	}
+}
+
+func TestMultiErrorCanceled(t *testing.T) {
+	err := &MultiError{Errors: []AddressError{
+		{Address: "addr1", Err: errors.New("connection error")},
+		{Address: "addr2", Err: context.Canceled},
+	}}
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("errors.Is(%v, context.Canceled) = false, want true", err)
+	}
+
+	if want := "addr1: connection error\naddr2: context canceled"; err.Error() != want {
+		t.Fatalf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestErrAllFailed(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection error")}},
+		"addr2": {"key1": {Value: "value2"}},
+		"slow":  {"key1": {Value: "slow", Delay: time.Second}},
+	})
+
+	tests := []struct {
+		name    string
+		call    func(ctx context.Context) error
+		wantAll bool
+	}{
+		{
+			name: "Get: все адреса упали",
+			call: func(ctx context.Context) error {
+				_, err := Get(ctx, mock, []string{"addr1", "addr3"}, "key1")
+				return err
+			},
+			wantAll: true,
+		},
+		{
+			name: "GetAll: все адреса упали",
+			call: func(ctx context.Context) error {
+				_, err := GetAll(ctx, mock, []string{"addr1", "addr3"}, "key1")
+				return err
+			},
+			wantAll: true,
+		},
+		{
+			name: "GetTiered: все ярусы упали",
+			call: func(ctx context.Context) error {
+				_, err := GetTiered(ctx, mock, [][]string{{"addr1"}, {"addr3"}}, "key1")
+				return err
+			},
+			wantAll: true,
+		},
+		{
+			name: "успех",
+			call: func(ctx context.Context) error {
+				_, err := Get(ctx, mock, []string{"addr1", "addr2"}, "key1")
+				return err
+			},
+		},
+		{
+			name: "истёк контекст",
+			call: func(ctx context.Context) error {
+				_, err := Get(ctx, mock, []string{"addr1", "slow"}, "key1")
+				return err
+			},
+		},
+		{
+			name: "ключа нет нигде",
+			call: func(ctx context.Context) error {
+				_, err := Get(ctx, &MapGetter{}, []string{"addr1", "addr2"}, "key1")
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			if err := tt.call(ctx); errors.Is(err, ErrAllFailed) != tt.wantAll {
+				t.Fatalf("error = %v, want errors.Is(err, ErrAllFailed) == %v", err, tt.wantAll)
+			}
+		})
+	}
+}
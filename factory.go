@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// GetWithFactory works like Get but builds the getter of every address with
+// factory, lazily: factory is called at most once per address, and only for
+// the addresses actually queried, which matters once WithMaxConcurrency or
+// WithSequential keep some of them from being reached. A nil getter from
+// factory fails its address.
+func GetWithFactory(ctx context.Context, factory func(address string) Getter, addresses []string, key string, opts ...Option) (string, error) {
+	var getter Getter
+	if factory != nil {
+		getter = &factoryGetter{factory: factory, getters: make(map[string]*lazyGetter)}
+	}
+
+	return Get(ctx, getter, addresses, key, opts...)
+}
+
+// factoryGetter dispatches every call to the getter built for its address.
+type factoryGetter struct {
+	factory func(address string) Getter
+
+	mu      sync.Mutex
+	getters map[string]*lazyGetter
+}
+
+type lazyGetter struct {
+	once   sync.Once
+	getter Getter
+}
+
+func (f *factoryGetter) Get(ctx context.Context, address, key string) (string, error) {
+	f.mu.Lock()
+	lazy, ok := f.getters[address]
+	if !ok {
+		lazy = &lazyGetter{}
+		f.getters[address] = lazy
+	}
+	f.mu.Unlock()
+
+	// Building outside the lock keeps a slow factory from holding up the
+	// other addresses.
+	lazy.once.Do(func() { lazy.getter = f.factory(address) })
+	if lazy.getter == nil {
+		return "", fmt.Errorf("factory returned no getter: %w", ErrNilGetter)
+	}
+
+	return lazy.getter.Get(ctx, address, key)
+}
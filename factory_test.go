@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetWithFactory(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection error")}},
+		"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+		"addr3": {"key1": {Value: "value3"}},
+	})
+
+	tests := []struct {
+		name      string
+		addresses []string
+		opts      []Option
+		wantBuilt map[string]int
+	}{
+		{
+			name:      "последовательно — фабрика только для достигнутых адресов",
+			addresses: []string{"addr1", "addr2", "addr3"},
+			opts:      []Option{WithSequential()},
+			wantBuilt: map[string]int{"addr1": 1, "addr2": 1},
+		},
+		{
+			name:      "повторы не пересоздают getter",
+			addresses: []string{"addr1", "addr2"},
+			opts:      []Option{WithSequential(), WithRetry(3)},
+			wantBuilt: map[string]int{"addr1": 1, "addr2": 1},
+		},
+		{
+			name:      "дубликаты адреса — одна сборка",
+			addresses: []string{"addr1", "addr1", "addr2"},
+			wantBuilt: map[string]int{"addr1": 1, "addr2": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			built := map[string]int{}
+			factory := func(address string) Getter {
+				mu.Lock()
+				defer mu.Unlock()
+
+				built[address]++
+				return mock
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetWithFactory(ctx, factory, tt.addresses, "key1", tt.opts...)
+			if err != nil || got != "value2" {
+				t.Fatalf("GetWithFactory() = (%q, %v), want (%q, nil)", got, err, "value2")
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if !maps.Equal(built, tt.wantBuilt) {
+				t.Fatalf("factory calls = %v, want %v", built, tt.wantBuilt)
+			}
+		})
+	}
+
+	t.Run("нет фабрики", func(t *testing.T) {
+		if _, err := GetWithFactory(context.Background(), nil, []string{"addr1"}, "key1"); !errors.Is(err, ErrNilGetter) {
+			t.Fatalf("GetWithFactory() error = %v, want %v", err, ErrNilGetter)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// GetOrDefault works like Get but returns fallback instead of an error when
+// every address failed. Errors that are not backend failures, namely a done
+// ctx and invalid arguments, are still returned as is.
+func GetOrDefault(ctx context.Context, getter Getter, addresses []string, key, fallback string, opts ...Option) (string, error) {
+	value, err := Get(ctx, getter, addresses, key, opts...)
+	if err == nil {
+		return value, nil
+	}
+
+	if ctx.Err() != nil || errors.Is(err, ErrNilGetter) || errors.Is(err, ErrEmptyKey) {
+		return "", err
+	}
+
+	return fallback, nil
+}
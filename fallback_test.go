@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetOrDefault(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		ttl       time.Duration
+		wantValue string
+		wantErrIs error
+	}{
+		{
+			name: "успех возвращает настоящее значение",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			ttl:       time.Second,
+			wantValue: "value2",
+		},
+		{
+			name: "все адреса падают — значение по умолчанию",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("error 1")}},
+				"addr2": {},
+			},
+			ttl:       time.Second,
+			wantValue: "fallback",
+		},
+		{
+			name: "отмена контекста — ошибка, а не значение по умолчанию",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: time.Second}},
+				"addr2": {"key1": {Value: "value2", Delay: time.Second}},
+			},
+			ttl:       30 * time.Millisecond,
+			wantErrIs: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ttl)
+			defer cancel()
+
+			got, err := GetOrDefault(ctx, NewMockGetter(tt.responses), []string{"addr1", "addr2"}, "key1", "fallback")
+
+			if !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("GetOrDefault() error = %v, want %v", err, tt.wantErrIs)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetOrDefault() = %q, want %q", got, tt.wantValue)
+			}
+		})
+	}
+}
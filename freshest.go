@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// GetFreshest queries every address, treating earlier addresses as more
+// up to date than later ones. Once the first success arrives it waits up to
+// graceWindow for a success from an address listed before it, and returns
+// the value of the earliest-listed address that answered by then. It stops
+// waiting as soon as every address listed before the current best one has
+// answered. Options choosing and ordering the addresses apply as in Get, and
+// the order they leave is the one that ranks freshness. Options pacing the
+// starts, such as WithMaxConcurrency and WithHedgeDelay, do not apply: every
+// address is queried at once.
+func GetFreshest(ctx context.Context, getter Getter, addresses []string, key string, graceWindow time.Duration, opts ...Option) (value string, err error) {
+	cfg := newConfig(opts)
+	if err := validate[string](getter, key); err != nil {
+		return "", err
+	}
+
+	cfg = cfg.withContext(ctx)
+	if err := checkDeadline(ctx, cfg.deadlineSlack); err != nil {
+		return "", err
+	}
+
+	addresses, err = prepare(addresses, key, cfg)
+	if err != nil || len(addresses) == 0 {
+		return "", err
+	}
+
+	// The attempts still running are told whether they lost to a winner.
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer func() {
+		if err == nil {
+			cancel(ErrWinnerFound)
+		} else {
+			cancel(nil)
+		}
+	}()
+
+	results := make(chan result[string], len(addresses))
+	for i, address := range addresses {
+		go func() {
+			value, err := query[string](ctx, getter, address, key, cfg)
+			results <- result[string]{index: i, address: address, value: value, err: err}
+		}()
+	}
+
+	var grace <-chan time.Time
+	best := -1
+	answered := make([]bool, len(addresses))
+	errs := make([]AddressError, len(addresses))
+	for range addresses {
+		select {
+		case r := <-results:
+			answered[r.index] = true
+			if r.err != nil {
+				errs[r.index] = AddressError{Address: r.address, Err: r.err}
+			} else if best < 0 || r.index < best {
+				best, value = r.index, r.value
+				if grace == nil {
+					timer := cfg.timeSource().NewTimer(graceWindow)
+					defer timer.Stop()
+					grace = timer.C()
+				}
+			}
+
+			if best >= 0 && !slices.Contains(answered[:best], false) {
+				return value, nil
+			}
+		case <-grace:
+			return value, nil
+		case <-ctx.Done():
+			return "", canceled(ctx)
+		}
+	}
+
+	return "", &MultiError{Errors: errs}
+}
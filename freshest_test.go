@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetFreshest(t *testing.T) {
+	open := NewBreaker(1, time.Minute)
+	open.record("primary", errors.New("connection error"))
+
+	tests := []struct {
+		name        string
+		responses   map[string]map[string]Response
+		opts        []Option
+		graceWindow time.Duration
+		wantValue   string
+		wantErr     bool
+		maxElapsed  time.Duration
+	}{
+		{
+			name: "приоритетный медленный адрес успевает в окно",
+			responses: map[string]map[string]Response{
+				"primary": {"key1": {Value: "fresh", Delay: 30 * time.Millisecond}},
+				"replica": {"key1": {Value: "stale"}},
+			},
+			graceWindow: 200 * time.Millisecond,
+			wantValue:   "fresh",
+			maxElapsed:  150 * time.Millisecond,
+		},
+		{
+			name: "приоритетный адрес не успевает в окно",
+			responses: map[string]map[string]Response{
+				"primary": {"key1": {Value: "fresh", Delay: 500 * time.Millisecond}},
+				"replica": {"key1": {Value: "stale"}},
+			},
+			graceWindow: 20 * time.Millisecond,
+			wantValue:   "stale",
+			maxElapsed:  200 * time.Millisecond,
+		},
+		{
+			name: "приоритетный адрес падает — окно не выжидается",
+			responses: map[string]map[string]Response{
+				"primary": {"key1": {Error: errors.New("connection error"), Delay: 10 * time.Millisecond}},
+				"replica": {"key1": {Value: "stale"}},
+			},
+			graceWindow: time.Second,
+			wantValue:   "stale",
+			maxElapsed:  200 * time.Millisecond,
+		},
+		{
+			name: "нездоровый приоритетный адрес не запрашивается",
+			responses: map[string]map[string]Response{
+				"primary": {"key1": {Value: "fresh", Delay: 30 * time.Millisecond}},
+				"replica": {"key1": {Value: "stale"}},
+			},
+			opts:        []Option{WithHealthChecker(func(address string) bool { return address != "primary" })},
+			graceWindow: time.Second,
+			wantValue:   "stale",
+			maxElapsed:  200 * time.Millisecond,
+		},
+		{
+			name: "адрес с открытой цепью не запрашивается",
+			responses: map[string]map[string]Response{
+				"primary": {"key1": {Value: "fresh", Delay: 30 * time.Millisecond}},
+				"replica": {"key1": {Value: "stale"}},
+			},
+			opts:        []Option{WithBreaker(open)},
+			graceWindow: time.Second,
+			wantValue:   "stale",
+			maxElapsed:  200 * time.Millisecond,
+		},
+		{
+			name: "все адреса падают",
+			responses: map[string]map[string]Response{
+				"primary": {"key1": {Error: errors.New("error 1")}},
+				"replica": {"key1": {Error: errors.New("error 2")}},
+			},
+			graceWindow: time.Second,
+			wantErr:     true,
+			maxElapsed:  200 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			start := time.Now()
+			got, err := GetFreshest(ctx, NewMockGetter(tt.responses), []string{"primary", "replica"}, "key1", tt.graceWindow, tt.opts...)
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetFreshest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetFreshest() = %q, want %q", got, tt.wantValue)
+			}
+
+			if elapsed > tt.maxElapsed {
+				t.Fatalf("GetFreshest() took %v, want at most %v", elapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
+
+func TestGetFreshestCancelsLosersWithWinnerFound(t *testing.T) {
+	getter := causeRecordingGetter{causes: make(chan error, 1)}
+
+	got, err := GetFreshest(context.Background(), getter, []string{"fast", "slow"}, "key1", time.Second)
+	if err != nil || got != "value-fast" {
+		t.Fatalf("GetFreshest() = (%q, %v), want (%q, nil)", got, err, "value-fast")
+	}
+
+	select {
+	case cause := <-getter.causes:
+		if !errors.Is(cause, ErrWinnerFound) {
+			t.Fatalf("loser saw cause %v, want %v", cause, ErrWinnerFound)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("loser not cancelled")
+	}
+}
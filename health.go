@@ -0,0 +1,31 @@
+package main
+
+import "errors"
+
+var ErrNoHealthyAddresses = errors.New("no healthy addresses")
+
+// WithHealthChecker makes Get skip the addresses healthy reports as down.
+// healthy is called at most once per distinct address per call.
+func WithHealthChecker(healthy func(address string) bool) Option {
+	return func(c *config) {
+		c.healthy = healthy
+	}
+}
+
+func filterHealthy(addresses []string, healthy func(address string) bool) []string {
+	checked := make(map[string]bool, len(addresses))
+	kept := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		ok, seen := checked[address]
+		if !seen {
+			ok = healthy(address)
+			checked[address] = ok
+		}
+
+		if ok {
+			kept = append(kept, address)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGetWithHealthChecker(t *testing.T) {
+	tests := []struct {
+		name      string
+		healthy   map[string]bool
+		wantValue string
+		wantErrIs error
+		wantCalls map[string]int
+	}{
+		{
+			name:      "нездоровые адреса не опрашиваются",
+			healthy:   map[string]bool{"addr1": false, "addr2": true, "addr3": false},
+			wantValue: "value2",
+			wantCalls: map[string]int{"addr1": 0, "addr2": 1, "addr3": 0},
+		},
+		{
+			name:      "все адреса нездоровы",
+			healthy:   map[string]bool{},
+			wantErrIs: ErrNoHealthyAddresses,
+			wantCalls: map[string]int{"addr1": 0, "addr2": 0, "addr3": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockGetter(map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1"}},
+				"addr2": {"key1": {Value: "value2"}},
+				"addr3": {"key1": {Value: "value3"}},
+			})
+			getter := newCountingGetter(mock)
+
+			checks := map[string]int{}
+			healthy := func(address string) bool {
+				checks[address]++
+				return tt.healthy[address]
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			addresses := []string{"addr1", "addr2", "addr3", "addr1"}
+			got, err := Get(ctx, getter, addresses, "key1", WithHealthChecker(healthy))
+
+			if !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("Get() error = %v, want %v", err, tt.wantErrIs)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("Get() = %q, want %q", got, tt.wantValue)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+
+			for address, n := range checks {
+				if n != 1 {
+					t.Fatalf("health of %s checked %d times, want once", address, n)
+				}
+			}
+		})
+	}
+}
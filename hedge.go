@@ -0,0 +1,65 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// hedgePercentile is the percentile of the recorded latencies a
+// LatencyTracker hedges at.
+const hedgePercentile = 95
+
+// LatencyTracker remembers the latencies of the last window successful
+// answers and derives a hedge delay from them, so that hedging tunes itself
+// to how fast the backends actually are. A LatencyTracker is safe for
+// concurrent use and is meant to be shared by many Get calls.
+type LatencyTracker struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	next      int
+}
+
+func NewLatencyTracker(window int) *LatencyTracker {
+	return &LatencyTracker{latencies: make([]time.Duration, 0, max(window, 1))}
+}
+
+// WithAdaptiveHedge hedges like WithHedgeDelay, but after the delay t
+// currently suggests, and records the latency of every success Get waits
+// for back into t. Until t has recorded anything the delay set by
+// WithHedgeDelay, if any, is used.
+func WithAdaptiveHedge(t *LatencyTracker) Option {
+	return func(c *config) {
+		c.latencies = t
+	}
+}
+
+// Record adds the latency of a successful answer, replacing the oldest one
+// once the window is full.
+func (t *LatencyTracker) Record(latency time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.latencies) < cap(t.latencies) {
+		t.latencies = append(t.latencies, latency)
+		return
+	}
+
+	t.latencies[t.next] = latency
+	t.next = (t.next + 1) % len(t.latencies)
+}
+
+// HedgeDelay returns the 95th percentile of the recorded latencies, or zero
+// when none has been recorded yet.
+func (t *LatencyTracker) HedgeDelay() time.Duration {
+	t.mu.Lock()
+	sorted := slices.Clone(t.latencies)
+	t.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	slices.Sort(sorted)
+	return percentile(sorted, hedgePercentile)
+}
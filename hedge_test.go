@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLatencyTrackerHedgeDelay(t *testing.T) {
+	ms := func(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+
+	tests := []struct {
+		name      string
+		window    int
+		latencies []time.Duration
+		want      time.Duration
+	}{
+		{name: "нет данных", window: 10},
+		{name: "одно значение", window: 10, latencies: []time.Duration{ms(7)}, want: ms(7)},
+		{
+			name:   "95-й перцентиль ста значений",
+			window: 100,
+			latencies: func() []time.Duration {
+				var l []time.Duration
+				for i := 100; i >= 1; i-- {
+					l = append(l, ms(i))
+				}
+				return l
+			}(),
+			want: ms(95),
+		},
+		{
+			name:      "старые значения вытесняются",
+			window:    3,
+			latencies: []time.Duration{ms(500), ms(400), ms(10), ms(20), ms(30)},
+			want:      ms(30),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewLatencyTracker(tt.window)
+			for _, l := range tt.latencies {
+				tracker.Record(l)
+			}
+
+			if got := tracker.HedgeDelay(); got != tt.want {
+				t.Fatalf("HedgeDelay() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetWithAdaptiveHedge(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"slow": {"key1": {Value: "slow", Delay: time.Second}},
+		"fast": {"key1": {Value: "fast", Delay: 10 * time.Millisecond}},
+	})
+
+	t.Run("задержка берётся из истории", func(t *testing.T) {
+		tracker := NewLatencyTracker(10)
+		tracker.Record(30 * time.Millisecond)
+
+		start := time.Now()
+		got, err := Get(context.Background(), newCountingGetter(mock), []string{"slow", "fast"}, "key1",
+			WithHedgeDelay(time.Hour), WithAdaptiveHedge(tracker))
+		if err != nil || got != "fast" {
+			t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "fast")
+		}
+
+		if elapsed := time.Since(start); elapsed < 40*time.Millisecond || elapsed > 500*time.Millisecond {
+			t.Fatalf("Get() took %v, want the hedge to fire after about 30ms", elapsed)
+		}
+
+		if d := tracker.HedgeDelay(); d < 10*time.Millisecond {
+			t.Fatalf("HedgeDelay() = %v, want the winner's latency recorded", d)
+		}
+	})
+
+	t.Run("без истории действует обычная задержка", func(t *testing.T) {
+		tracker := NewLatencyTracker(10)
+		getter := newCountingGetter(mock)
+
+		got, err := Get(context.Background(), getter, []string{"fast", "slow"}, "key1",
+			WithHedgeDelay(time.Hour), WithAdaptiveHedge(tracker))
+		if err != nil || got != "fast" {
+			t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "fast")
+		}
+
+		if n := getter.Calls("slow"); n != 0 {
+			t.Fatalf("calls to slow = %d, want 0", n)
+		}
+	})
+}
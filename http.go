@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// StatusError is returned by HTTPGetter for a response outside 2xx.
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return "unexpected HTTP status " + e.Status
+}
+
+// HTTPGetter is a Getter backed by HTTP GET requests. The URL of a request
+// is its template with "{address}" replaced by the address and "{key}" by
+// the path-escaped key, e.g. "http://{address}/values/{key}". The response
+// body is the value. An HTTPGetter is safe for concurrent use.
+type HTTPGetter struct {
+	client   *http.Client
+	template string
+}
+
+// NewHTTPGetter returns an HTTPGetter sending its requests with client, or
+// with http.DefaultClient when client is nil.
+func NewHTTPGetter(template string, client *http.Client) *HTTPGetter {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &HTTPGetter{client: client, template: template}
+}
+
+func (g *HTTPGetter) Get(ctx context.Context, address, key string) (string, error) {
+	target := strings.NewReplacer("{address}", address, "{key}", url.PathEscape(key)).Replace(g.template)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		// Draining the body lets the connection be reused.
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return "", &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("read body: %w", err)
+	}
+
+	return string(body), nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPGetter(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/values/{key}", func(w http.ResponseWriter, r *http.Request) {
+		switch key := r.PathValue("key"); key {
+		case "broken":
+			http.Error(w, "boom", http.StatusInternalServerError)
+		case "slow":
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+		default:
+			w.Write([]byte("value of " + key))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	address := strings.TrimPrefix(server.URL, "http://")
+	getter := NewHTTPGetter("http://{address}/values/{key}", server.Client())
+
+	tests := []struct {
+		name       string
+		key        string
+		wantValue  string
+		wantStatus int
+		wantErrIs  error
+	}{
+		{
+			name:      "успешный ответ",
+			key:       "key1",
+			wantValue: "value of key1",
+		},
+		{
+			name:      "ключ экранируется",
+			key:       "a b",
+			wantValue: "value of a b",
+		},
+		{
+			name:       "ответ 500",
+			key:        "broken",
+			wantStatus: http.StatusInternalServerError,
+		},
+		{
+			name:      "таймаут",
+			key:       "slow",
+			wantErrIs: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			got, err := getter.Get(ctx, address, tt.key)
+
+			if got != tt.wantValue {
+				t.Fatalf("Get() = %q, want %q", got, tt.wantValue)
+			}
+
+			var statusErr *StatusError
+			if tt.wantStatus != 0 && (!errors.As(err, &statusErr) || statusErr.StatusCode != tt.wantStatus) {
+				t.Fatalf("Get() error = %v, want status %d", err, tt.wantStatus)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("Get() error = %v, want errors.Is(err, %v) == true", err, tt.wantErrIs)
+			}
+
+			if tt.wantValue != "" && err != nil {
+				t.Fatalf("Get() error = %v, want nil", err)
+			}
+		})
+	}
+
+	t.Run("вместе с Get", func(t *testing.T) {
+		got, err := Get(context.Background(), getter, []string{"127.0.0.1:1", address}, "key1")
+		if err != nil || got != "value of key1" {
+			t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value of key1")
+		}
+	})
+}
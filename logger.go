@@ -0,0 +1,12 @@
+package main
+
+import "log/slog"
+
+// WithLogger logs the lifecycle of every getter call to logger at debug
+// level: when it starts and whether it succeeded, failed or was cancelled
+// because the operation ended first.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *config) {
+		c.logger = logger
+	}
+}
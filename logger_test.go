@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type capturingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *capturingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *capturingHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.records = append(h.records, r.Clone())
+	return nil
+}
+
+func (h *capturingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *capturingHandler) WithGroup(string) slog.Handler { return h }
+
+func (h *capturingHandler) Records() []slog.Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return slices.Clone(h.records)
+}
+
+func recordAttrs(r slog.Record) map[string]slog.Value {
+	attrs := map[string]slog.Value{}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+
+	return attrs
+}
+
+func TestGetWithLogger(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection error"), Delay: 10 * time.Millisecond}},
+		"addr2": {"key1": {Value: "value2", Delay: 30 * time.Millisecond}},
+	})
+	handler := &capturingHandler{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	got, err := Get(ctx, mock, []string{"addr1", "addr2"}, "key1", WithLogger(slog.New(handler)))
+	if err != nil || got != "value2" {
+		t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value2")
+	}
+
+	want := map[string]string{
+		"attempt failed":    "addr1",
+		"attempt succeeded": "addr2",
+	}
+	started := 0
+	for _, r := range handler.Records() {
+		if r.Level != slog.LevelDebug {
+			t.Fatalf("record %q logged at %v, want debug", r.Message, r.Level)
+		}
+
+		attrs := recordAttrs(r)
+		if attrs["key"].String() != "key1" {
+			t.Fatalf("record %q has key %q, want key1", r.Message, attrs["key"])
+		}
+
+		if r.Message == "attempt started" {
+			started++
+			continue
+		}
+
+		address, ok := want[r.Message]
+		if !ok {
+			t.Fatalf("unexpected record %q", r.Message)
+		}
+		delete(want, r.Message)
+
+		if attrs["address"].String() != address {
+			t.Fatalf("record %q has address %q, want %s", r.Message, attrs["address"], address)
+		}
+
+		if _, ok := attrs["elapsed"]; !ok {
+			t.Fatalf("record %q has no elapsed time", r.Message)
+		}
+
+		if _, ok := attrs["error"]; !ok && r.Message == "attempt failed" {
+			t.Fatalf("record %q has no error", r.Message)
+		}
+	}
+
+	if started != 2 || len(want) != 0 {
+		t.Fatalf("got %d start records and missing %v, want 2 starts and no missing records", started, want)
+	}
+}
+
+func TestGetWithLoggerCancelled(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"slow": {"key1": {Value: "slow", Delay: time.Second}},
+		"fast": {"key1": {Value: "fast"}},
+	})
+	handler := &capturingHandler{}
+
+	if _, err := Get(context.Background(), mock, []string{"slow", "fast"}, "key1", WithLogger(slog.New(handler))); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		for _, r := range handler.Records() {
+			if r.Message == "attempt cancelled" && recordAttrs(r)["address"].String() == "slow" {
+				return
+			}
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	t.Fatal("no cancellation record for the losing attempt")
+}
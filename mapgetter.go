@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// MapGetter is a Getter serving values from memory, meant for the test
+// suites of code built on Get. Responses maps an address to the values it
+// holds by key. An address listed in Errors always fails with that error,
+// and one listed in Delays answers only after that delay, or fails with the
+// context error if ctx is done first. Looking up a key an address does not
+// hold fails with ErrKeyNotFound. The maps must not be modified while the
+// getter is used.
+type MapGetter struct {
+	Responses map[string]map[string]string
+	Delays    map[string]time.Duration
+	Errors    map[string]error
+}
+
+func (m *MapGetter) Get(ctx context.Context, address, key string) (string, error) {
+	if d := m.Delays[address]; d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	if err := m.Errors[address]; err != nil {
+		return "", err
+	}
+
+	value, ok := m.Responses[address][key]
+	if !ok {
+		return "", fmt.Errorf("%q at %s: %w", key, address, ErrKeyNotFound)
+	}
+
+	return value, nil
+}
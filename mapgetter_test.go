@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMapGetter(t *testing.T) {
+	errDown := errors.New("replica down")
+	getter := &MapGetter{
+		Responses: map[string]map[string]string{
+			"addr1": {"key1": "value1", "empty": ""},
+			"slow":  {"key1": "slow"},
+			"down":  {"key1": "unreachable"},
+		},
+		Delays: map[string]time.Duration{"slow": time.Second},
+		Errors: map[string]error{"down": errDown},
+	}
+
+	tests := []struct {
+		name      string
+		address   string
+		key       string
+		wantValue string
+		wantErrIs error
+		wantErr   bool
+	}{
+		{name: "значение по ключу", address: "addr1", key: "key1", wantValue: "value1"},
+		{name: "пустое значение", address: "addr1", key: "empty", wantValue: ""},
+		{name: "нет ключа", address: "addr1", key: "missing", wantErr: true, wantErrIs: ErrKeyNotFound},
+		{name: "неизвестный адрес", address: "addr9", key: "key1", wantErr: true, wantErrIs: ErrKeyNotFound},
+		{name: "ошибка адреса", address: "down", key: "key1", wantErr: true, wantErrIs: errDown},
+		{name: "задержка прерывается контекстом", address: "slow", key: "key1", wantErr: true, wantErrIs: context.DeadlineExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+			defer cancel()
+
+			got, err := getter.Get(ctx, tt.address, tt.key)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("Get() error = %v, want errors.Is(err, %v) == true", err, tt.wantErrIs)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("Get() = %q, want %q", got, tt.wantValue)
+			}
+		})
+	}
+
+	t.Run("вместе с Get", func(t *testing.T) {
+		got, err := Get(context.Background(), getter, []string{"down", "addr1"}, "key1")
+		if err != nil || got != "value1" {
+			t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value1")
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package main
+
+import "time"
+
+// Metrics receives a callback around every getter call made by Get. The
+// callbacks may be invoked concurrently from several attempts.
+type Metrics interface {
+	OnAttemptStart(address, key string)
+	OnAttemptSuccess(address, key string, latency time.Duration)
+	OnAttemptError(address, key string, err error, latency time.Duration)
+}
+
+// WithMetrics reports every getter call to m.
+func WithMetrics(m Metrics) Option {
+	return func(c *config) {
+		c.metrics = m
+	}
+}
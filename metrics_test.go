@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingMetrics struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recordingMetrics) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *recordingMetrics) OnAttemptStart(address, key string) {
+	r.record("start " + address + " " + key)
+}
+
+func (r *recordingMetrics) OnAttemptSuccess(address, key string, latency time.Duration) {
+	r.record("success " + address + " " + key)
+}
+
+func (r *recordingMetrics) OnAttemptError(address, key string, err error, latency time.Duration) {
+	r.record("error " + address + " " + key + ": " + err.Error())
+}
+
+func (r *recordingMetrics) Events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.events)
+}
+
+func TestGetWithMetrics(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection error"), Delay: 20 * time.Millisecond}},
+		"addr2": {"key1": {Value: "value2", Delay: 40 * time.Millisecond}},
+	})
+	metrics := &recordingMetrics{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	got, err := Get(ctx, mock, []string{"addr1", "addr2"}, "key1", WithMetrics(metrics))
+	if err != nil || got != "value2" {
+		t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value2")
+	}
+
+	events := metrics.Events()
+	if len(events) != 4 {
+		t.Fatalf("got events %q, want 4", events)
+	}
+
+	starts := slices.Sorted(slices.Values(events[:2]))
+	if want := []string{"start addr1 key1", "start addr2 key1"}; !slices.Equal(starts, want) {
+		t.Fatalf("first events = %q, want %q in any order", events[:2], want)
+	}
+
+	if want := []string{"error addr1 key1: connection error", "success addr2 key1"}; !slices.Equal(events[2:], want) {
+		t.Fatalf("last events = %q, want %q", events[2:], want)
+	}
+}
+
+func TestGetWithoutMetricsAllocations(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1"}},
+	})
+	ctx := context.Background()
+	addresses := []string{"addr1"}
+
+	plain := testing.AllocsPerRun(100, func() {
+		race[string](ctx, mock, addresses, "key1", config{})
+	})
+	withOpts := testing.AllocsPerRun(100, func() {
+		Get(ctx, mock, addresses, "key1")
+	})
+
+	if withOpts > plain {
+		t.Fatalf("Get() without metrics allocates %v times per call, want at most %v", withOpts, plain)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// GetMulti resolves every key with the same race as Get, all keys at once.
+// WithMaxConcurrency caps the getter calls in flight across all keys rather
+// than per key. The returned map holds the keys that resolved; the error
+// lists the ones that did not. All keys share ctx, so cancelling it aborts
+// every key that has not resolved yet.
+func GetMulti(ctx context.Context, getter Getter, addresses []string, keys []string, opts ...Option) (map[string]string, error) {
+	cfg := newConfig(opts)
+	if cfg.maxConcurrency > 0 {
+		cfg.slots = make(chan struct{}, cfg.maxConcurrency)
+		cfg.maxConcurrency = 0
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		values = make(map[string]string, len(keys))
+		errs   = make(map[string]error)
+	)
+
+	keys = dedup(keys)
+	for _, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			value, _, err := race[string](ctx, getter, addresses, key, cfg)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs[key] = err
+			} else {
+				values[key] = value
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	var failed []error
+	for _, key := range keys {
+		if err, ok := errs[key]; ok {
+			failed = append(failed, fmt.Errorf("key %q: %w", key, err))
+		}
+	}
+
+	return values, errors.Join(failed...)
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetMulti(t *testing.T) {
+	errConn := errors.New("connection error")
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {
+			"key1": {Value: "value1"},
+			"key2": {Error: errConn},
+			"key3": {Error: errConn},
+		},
+		"addr2": {
+			"key1": {Error: errConn},
+			"key2": {Value: "value2", Delay: 10 * time.Millisecond},
+			"key3": {Error: errConn},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		keys       []string
+		want       map[string]string
+		wantErr    bool
+		wantFailed []string
+	}{
+		{
+			name: "все ключи найдены",
+			keys: []string{"key1", "key2"},
+			want: map[string]string{"key1": "value1", "key2": "value2"},
+		},
+		{
+			name:       "частичный успех",
+			keys:       []string{"key1", "key2", "key3", "key4"},
+			want:       map[string]string{"key1": "value1", "key2": "value2"},
+			wantErr:    true,
+			wantFailed: []string{`key "key3"`, `key "key4"`},
+		},
+		{
+			name: "пустой список ключей",
+			keys: nil,
+			want: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetMulti(ctx, mock, []string{"addr1", "addr2"}, tt.keys)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetMulti() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !maps.Equal(got, tt.want) {
+				t.Fatalf("GetMulti() = %v, want %v", got, tt.want)
+			}
+
+			if tt.wantErr && !errors.Is(err, errConn) {
+				t.Fatalf("GetMulti() error = %v, want errors.Is(err, %v) == true", err, errConn)
+			}
+
+			for _, want := range tt.wantFailed {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("GetMulti() error = %q, want it to mention %s", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetMultiSharedConcurrency(t *testing.T) {
+	responses := map[string]map[string]Response{"addr1": {}, "addr2": {}}
+	keys := []string{"key1", "key2", "key3", "key4"}
+	for _, key := range keys {
+		responses["addr1"][key] = Response{Value: "value", Delay: 20 * time.Millisecond}
+		responses["addr2"][key] = Response{Value: "value", Delay: 20 * time.Millisecond}
+	}
+	getter := newCountingGetter(NewMockGetter(responses))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	got, err := GetMulti(ctx, getter, []string{"addr1", "addr2"}, keys, WithMaxConcurrency(3))
+	if err != nil || len(got) != len(keys) {
+		t.Fatalf("GetMulti() = (%v, %v), want all %d keys", got, err, len(keys))
+	}
+
+	if n := getter.MaxInFlight(); n > 3 {
+		t.Fatalf("max in flight = %d across keys, want at most 3", n)
+	}
+}
+
+func TestGetMultiCancel(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {
+			"fast": {Value: "fast"},
+			"slow": {Value: "slow", Delay: time.Second},
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	got, err := GetMulti(ctx, mock, []string{"addr1"}, []string{"fast", "slow"})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("GetMulti() error = %v, want errors.Is(err, context.Canceled) == true", err)
+	}
+
+	if want := map[string]string{"fast": "fast"}; !maps.Equal(got, want) {
+		t.Fatalf("GetMulti() = %v, want %v", got, want)
+	}
+}
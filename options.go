@@ -0,0 +1,373 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Option configures how Get queries the addresses.
+type Option func(*config)
+
+func newConfig(opts []Option) config {
+	if len(opts) == 0 {
+		return config{}
+	}
+
+	cfg := new(config)
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return *cfg
+}
+
+// WithMaxConcurrency keeps at most n getter calls in flight, starting the next
+// queued address as each attempt finishes. An n of 0 or less means no limit.
+func WithMaxConcurrency(n int) Option {
+	return func(c *config) {
+		c.maxConcurrency = n
+	}
+}
+
+// WithSequential turns Get into classic failover: the addresses are queried
+// one at a time, in order, each only after the previous one failed. It
+// overrides WithMaxConcurrency and WithHedgeDelay.
+func WithSequential() Option {
+	return func(c *config) {
+		c.sequential = true
+	}
+}
+
+// WithHedgeDelay staggers the attempts: the first address is queried
+// immediately and each next one only after d passes without a success. A
+// failed attempt starts the next address right away. A d of zero queries
+// every address at once.
+func WithHedgeDelay(d time.Duration) Option {
+	return func(c *config) {
+		c.hedgeDelay = d
+	}
+}
+
+// WithAttemptTimeout gives every getter call its own deadline d on top of
+// the deadline of the caller's context. An attempt that runs out of time
+// counts as a failure of its address and the others carry on.
+func WithAttemptTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.attemptTimeout = d
+	}
+}
+
+// WithPerAddressTimeout gives the calls to each address in timeouts its own
+// deadline, replacing the one set by WithAttemptTimeout, which stays the
+// default for the other addresses. An address that runs out of its time
+// counts as failed and the others carry on.
+func WithPerAddressTimeout(timeouts map[string]time.Duration) Option {
+	return func(c *config) {
+		c.addressTimeouts = timeouts
+	}
+}
+
+// WithRetry calls each address up to maxAttempts times before counting it as
+// failed. Retries stop as soon as any address succeeds. A maxAttempts of 1 or
+// less makes a single call per address.
+func WithRetry(maxAttempts int) Option {
+	return func(c *config) {
+		c.maxAttempts = maxAttempts
+	}
+}
+
+// WithBackoff waits between retries of the same address: before the n-th
+// retry it sleeps base * 2^(n-1), capped at maxBackoff when that is positive.
+// Waiting is aborted as soon as the context is done.
+func WithBackoff(base, maxBackoff time.Duration) Option {
+	return func(c *config) {
+		c.backoffBase, c.backoffMax = base, maxBackoff
+	}
+}
+
+// WithDeadlineSlack makes Get give up without querying anything when the
+// context has less than slack left before its deadline. A Get on a context
+// that is already done never queries anything, with or without this option.
+func WithDeadlineSlack(slack time.Duration) Option {
+	return func(c *config) {
+		c.deadlineSlack = slack
+	}
+}
+
+// WithDedup queries each distinct address once, even if it is listed several
+// times. Without it duplicates are queried as many times as they appear.
+func WithDedup() Option {
+	return func(c *config) {
+		c.dedup = true
+	}
+}
+
+// WithAddressResolver queries the addresses resolve returns for the key
+// instead of the ones passed to Get, for keys living only on some of the
+// replicas. An empty result is treated like an empty address list. With
+// GetMulti it is called for every key. In GetTiered and GetEndpoints it is
+// called once per call: every tier keeps the resolved addresses it lists,
+// and the resolved addresses no tier lists form a last tier of their own.
+// With GetWeighted and GetEndpoints a resolved address keeps the start delay
+// of its weight if it was passed, and starts without one otherwise.
+func WithAddressResolver(resolve func(key string) []string) Option {
+	return func(c *config) {
+		c.resolve = resolve
+	}
+}
+
+// WithMaxAddresses queries at most the first k addresses, after they have
+// been ordered (shuffled, weighted, arranged on a ring) and filtered, and
+// ignores the rest, to bound the cost of huge address lists. A k of zero or
+// less queries them all. In GetTiered and GetEndpoints it caps every tier.
+func WithMaxAddresses(k int) Option {
+	return func(c *config) {
+		c.maxAddresses = k
+	}
+}
+
+// leakGrace is how long an attempt may keep running after its operation
+// ended before WithLeakHook reports it.
+const leakGrace = 100 * time.Millisecond
+
+// WithLeakHook calls hook with the address of every attempt that is still
+// running leakGrace after Get returned, typically because its getter ignores
+// ctx. Get never waits for such attempts either way; the hook only makes
+// them visible. It is called from a goroutine of its own.
+func WithLeakHook(hook func(address string)) Option {
+	return func(c *config) {
+		c.leakHook = hook
+	}
+}
+
+// WithRejectEmpty treats an empty value as a failure of the address that
+// returned it, with ErrEmptyValue, so Get moves on to the other addresses.
+// Use it only when no key can legitimately hold an empty value.
+func WithRejectEmpty() Option {
+	return func(c *config) {
+		c.rejectEmpty = true
+	}
+}
+
+// WithAcceptFunc counts a successful response as a winner only if accept
+// returns true for its value, such as to skip tombstone markers. A rejected
+// value fails its address with ErrNoAcceptableValue and Get goes on with the
+// others. It applies to the Get variants over strings and is ignored by the
+// typed ones.
+func WithAcceptFunc(accept func(value string) bool) Option {
+	return func(c *config) {
+		c.accept = accept
+	}
+}
+
+// WithDuplicateAddressHook calls hook once for every address listed more
+// than once, with the number of times it appears, before anything is
+// queried. It only reports duplicates: they are still queried as many times
+// as they appear unless WithDedup is used as well.
+func WithDuplicateAddressHook(hook func(address string, count int)) Option {
+	return func(c *config) {
+		c.onDuplicate = hook
+	}
+}
+
+// WithRetryableFunc lets retryable decide which errors are worth trying
+// another address, or the same one again, for. An error it rejects is
+// terminal: Get returns it immediately and cancels the other attempts.
+// Without it every error is retryable.
+func WithRetryableFunc(retryable func(error) bool) Option {
+	return func(c *config) {
+		c.retryable = retryable
+	}
+}
+
+// WithFailFastOn makes any error matching one of errs, as reported by
+// errors.Is, terminal: Get returns it immediately and cancels the other
+// attempts. It adds to the errors rejected by WithRetryableFunc.
+func WithFailFastOn(errs ...error) Option {
+	return func(c *config) {
+		c.failFast = slices.Concat(c.failFast, errs)
+	}
+}
+
+// WithJitter randomizes every backoff wait to a uniform duration between zero
+// and the computed delay, so that clients retrying at the same moment spread
+// out instead of hitting the backend together.
+func WithJitter() Option {
+	return func(c *config) {
+		c.jitter = true
+	}
+}
+
+// WithStartJitter delays the start of every attempt by a uniform random
+// duration in [0, maxDelay), on top of any delay it already has, so that many
+// clients fanning out at the same moment do not hit the backends in step. A
+// zero maxDelay disables it.
+func WithStartJitter(maxDelay time.Duration) Option {
+	return func(c *config) {
+		c.startJitter = maxDelay
+	}
+}
+
+// WithMaxLaunchRate starts at most perSecond attempts per second, evenly
+// spaced, so that a tiny hedge delay or a wide fan-out does not hit the
+// backends with every attempt at once. The first address always starts
+// immediately; the others are held back until their turn, on top of any
+// delay they already have. A perSecond of zero or less disables it.
+func WithMaxLaunchRate(perSecond int) Option {
+	return func(c *config) {
+		c.launchInterval = 0
+		if perSecond > 0 {
+			c.launchInterval = time.Second / time.Duration(perSecond)
+		}
+	}
+}
+
+// WithAbortOnFirstError turns Get into an all-or-nothing read: the first
+// address to fail, after its retries, cancels every other attempt and its
+// error is returned at once. By default a failure just moves on to the
+// remaining addresses.
+func WithAbortOnFirstError() Option {
+	return func(c *config) {
+		c.abortOnError = true
+	}
+}
+
+// WithMinResponses keeps Get from giving up on a terminal error, see
+// WithRetryableFunc, WithFailFastOn and WithAbortOnFirstError, before n
+// addresses have answered, so that a fast failure cannot hide a slower
+// success. With fewer than n addresses it waits for all of them.
+func WithMinResponses(n int) Option {
+	return func(c *config) {
+		c.minResponses = n
+	}
+}
+
+type config struct {
+	maxConcurrency    int
+	sequential        bool
+	hedgeDelay        time.Duration
+	hedgeAll          bool
+	latencies         *LatencyTracker
+	adaptive          *AdaptiveLimiter
+	attemptTimeout    time.Duration
+	addressTimeouts   map[string]time.Duration
+	maxAttempts       int
+	backoffBase       time.Duration
+	backoffMax        time.Duration
+	jitter            bool
+	startDelays       map[string]time.Duration
+	weightStagger     time.Duration
+	startJitter       time.Duration
+	launchInterval    time.Duration
+	rand              *randSource
+	clock             Clock
+	metrics           Metrics
+	resolve           func(key string) []string
+	dedup             bool
+	maxAddresses      int
+	onDuplicate       func(address string, count int)
+	breaker           *Breaker
+	limiters          map[string]Limiter
+	flights           *FlightGroup
+	inFlightDedup     bool
+	attempts          *FlightGroup
+	semaphore         Semaphore
+	slots             chan struct{}
+	retryable         func(error) bool
+	failFast          []error
+	rejectEmpty       bool
+	accept            func(value string) bool
+	minResponses      int
+	abortOnError      bool
+	selector          func(candidates []AddressResult) (AddressResult, bool)
+	partialOnDeadline bool
+	region            string
+	hash              func(string) uint64
+	ring              *Ring
+	healthy           func(address string) bool
+	logger            *slog.Logger
+	tracer            Tracer
+	leakHook          func(address string)
+	report            *report
+	calls             *atomic.Int64
+	softDeadline      time.Duration
+	deadlineSlack     time.Duration
+	budgeting         bool
+	budgetRounds      int
+	capAttempts       bool
+	budgetFloor       time.Duration
+}
+
+// terminal reports whether err ends the whole operation.
+func (c config) terminal(err error) bool {
+	for _, target := range c.failFast {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return c.retryable != nil && !c.retryable(err)
+}
+
+// timeoutFor returns the attempt timeout of address, zero meaning none.
+func (c config) timeoutFor(address string) time.Duration {
+	if d, ok := c.addressTimeouts[address]; ok {
+		return d
+	}
+
+	return c.attemptTimeout
+}
+
+// backoff returns how long to wait before the given retry, counting from 1.
+func (c config) backoff(retry int) time.Duration {
+	if c.backoffBase <= 0 {
+		return 0
+	}
+
+	delay := c.backoffBase
+	for range retry - 1 {
+		if c.backoffMax > 0 && delay >= c.backoffMax || delay > math.MaxInt64/2 {
+			break
+		}
+
+		delay *= 2
+	}
+
+	if c.backoffMax > 0 {
+		delay = min(delay, c.backoffMax)
+	}
+
+	if c.jitter {
+		delay = c.random().duration(delay + 1)
+	}
+
+	return delay
+}
+
+// startOffset returns how long the attempt on address waits before it
+// starts.
+func (c config) startOffset(address string) time.Duration {
+	d := c.startDelays[address]
+	if c.startJitter > 0 {
+		d += c.random().duration(c.startJitter)
+	}
+
+	return d
+}
+
+// concurrencyLimit returns how many addresses may be queried at once, zero
+// meaning all of them.
+func (c config) concurrencyLimit() int {
+	switch {
+	case c.sequential:
+		return 1
+	case c.adaptive != nil:
+		return c.adaptive.Limit()
+	default:
+		return c.maxConcurrency
+	}
+}
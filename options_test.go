@@ -0,0 +1,744 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		addresses []string
+		opts      []Option
+		wantValue string
+		wantErr   bool
+		wantCalls map[string]int
+	}{
+		{
+			name: "без опций — все адреса сразу, по одному вызову",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+				"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+				"addr3": {"key1": {Value: "value3", Delay: 10 * time.Millisecond}},
+			},
+			addresses: []string{"addr1", "addr2", "addr3"},
+			wantValue: "value3",
+			wantCalls: map[string]int{"addr1": 1, "addr2": 1, "addr3": 1},
+		},
+		{
+			name: "ограничение параллелизма и повторы",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			opts:      []Option{WithMaxConcurrency(1), WithRetry(3)},
+			wantValue: "value2",
+			wantCalls: map[string]int{"addr1": 3, "addr2": 1},
+		},
+		{
+			name: "хеджирование и таймаут попытки",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: time.Second}},
+				"addr2": {"key1": {Value: "value2", Delay: time.Second}},
+				"addr3": {"key1": {Value: "value3"}},
+			},
+			addresses: []string{"addr1", "addr2", "addr3"},
+			opts:      []Option{WithHedgeDelay(time.Second), WithAttemptTimeout(20 * time.Millisecond)},
+			wantValue: "value3",
+			wantCalls: map[string]int{"addr1": 1, "addr2": 1, "addr3": 1},
+		},
+		{
+			name: "повторы с задержкой исчерпаны",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+			},
+			addresses: []string{"addr1"},
+			opts:      []Option{WithRetry(3), WithBackoff(5*time.Millisecond, 10*time.Millisecond)},
+			wantErr:   true,
+			wantCalls: map[string]int{"addr1": 3},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(tt.responses))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := Get(ctx, getter, tt.addresses, "key1", tt.opts...)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Get() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("Get() = %q, want %q", got, tt.wantValue)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithDedup(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantCalls map[string]int
+	}{
+		{
+			name:      "с дедупликацией — один вызов на адрес",
+			opts:      []Option{WithDedup()},
+			wantCalls: map[string]int{"addr1": 1, "addr2": 1},
+		},
+		{
+			name:      "без дедупликации — дубликаты запрашиваются",
+			wantCalls: map[string]int{"addr1": 2, "addr2": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockGetter(map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("err")}},
+				"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+			})
+			getter := newCountingGetter(mock)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := Get(ctx, getter, []string{"addr1", "addr1", "addr2"}, "key1", tt.opts...)
+			if err != nil || got != "value2" {
+				t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value2")
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithAddressResolver(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1"}, "shared": {Value: "shared1"}},
+		"addr2": {"key2": {Value: "value2"}, "shared": {Value: "shared2"}},
+	})
+	resolve := func(key string) []string {
+		switch key {
+		case "key1":
+			return []string{"addr1"}
+		case "key2":
+			return []string{"addr2"}
+		case "shared":
+			return nil
+		}
+
+		return []string{"addr1", "addr2"}
+	}
+
+	tests := []struct {
+		name      string
+		key       string
+		want      string
+		wantErr   bool
+		wantCalls map[string]int
+	}{
+		{name: "ключ на одном адресе", key: "key1", want: "value1", wantCalls: map[string]int{"addr1": 1, "addr2": 0}},
+		{name: "ключ на другом адресе", key: "key2", want: "value2", wantCalls: map[string]int{"addr1": 0, "addr2": 1}},
+		{name: "пустой список — как без адресов", key: "shared", wantCalls: map[string]int{"addr1": 0, "addr2": 0}},
+		{name: "остальные ключи на всех", key: "other", wantErr: true, wantCalls: map[string]int{"addr1": 1, "addr2": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(mock)
+
+			got, err := Get(context.Background(), getter, []string{"addr2", "addr1"}, tt.key, WithAddressResolver(resolve))
+			if got != tt.want || (err != nil) != tt.wantErr {
+				t.Fatalf("Get() = (%q, %v), want %q and error %v", got, err, tt.want, tt.wantErr)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithAddressResolverWeighted(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"b": {"key1": {Error: errors.New("connection error")}},
+		"z": {"key1": {Value: "z", Delay: 10 * time.Millisecond}},
+	})
+	resolve := func(string) []string { return []string{"x", "y", "z", "b"} }
+	weighted := []WeightedAddress{{Address: "a", Weight: 2}, {Address: "b", Weight: 1}}
+
+	t.Run("GetWeighted", func(t *testing.T) {
+		got, err := GetWeighted(context.Background(), mock, weighted, "key1", WithAddressResolver(resolve))
+		if err != nil || got != "z" {
+			t.Fatalf("GetWeighted() = (%q, %v), want (%q, nil)", got, err, "z")
+		}
+	})
+
+	t.Run("PlanEndpoints", func(t *testing.T) {
+		endpoints := []Endpoint{{Address: "a", Weight: 2}, {Address: "b", Weight: 1}}
+		p := PlanEndpoints(endpoints, "key1", WithAddressResolver(resolve))
+		want := []PlannedAttempt{{Address: "x"}, {Address: "y"}, {Address: "z"}, {Address: "b", Start: weightStagger}}
+		if !slices.Equal(p.Attempts, want) {
+			t.Fatalf("Attempts = %+v, want %+v", p.Attempts, want)
+		}
+	})
+}
+
+func TestGetWithMaxAddresses(t *testing.T) {
+	addresses := []string{"addr1", "addr2", "addr3", "addr4", "addr5"}
+	weighted := []WeightedAddress{
+		{Address: "addr1", Weight: 1}, {Address: "addr2", Weight: 5}, {Address: "addr3", Weight: 1},
+		{Address: "addr4", Weight: 3}, {Address: "addr5", Weight: 0},
+	}
+
+	tests := []struct {
+		name    string
+		get     func(getter Getter) (string, error)
+		wantSet []string
+	}{
+		{
+			name: "первые два адреса",
+			get: func(getter Getter) (string, error) {
+				return Get(context.Background(), getter, addresses, "key1", WithMaxAddresses(2))
+			},
+			wantSet: []string{"addr1", "addr2"},
+		},
+		{
+			name: "после перемешивания",
+			get: func(getter Getter) (string, error) {
+				return GetShuffled(context.Background(), getter, addresses, "key1",
+					WithMaxAddresses(2), WithRand(rand.New(rand.NewPCG(1, 2))))
+			},
+			wantSet: []string{"addr2", "addr5"},
+		},
+		{
+			name: "самые тяжёлые по весу",
+			get: func(getter Getter) (string, error) {
+				return GetWeighted(context.Background(), getter, weighted, "key1", WithMaxAddresses(2))
+			},
+			wantSet: []string{"addr2", "addr4"},
+		},
+		{
+			name: "ноль не ограничивает",
+			get: func(getter Getter) (string, error) {
+				return Get(context.Background(), getter, addresses, "key1", WithMaxAddresses(0))
+			},
+			wantSet: addresses,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(nil))
+
+			_, err := tt.get(getter)
+			var multiErr *MultiError
+			if !errors.As(err, &multiErr) || len(multiErr.Errors) != len(tt.wantSet) {
+				t.Fatalf("error = %v, want a MultiError of %d failures", err, len(tt.wantSet))
+			}
+
+			for _, address := range addresses {
+				want := 0
+				if slices.Contains(tt.wantSet, address) {
+					want = 1
+				}
+
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithDuplicateAddressHook(t *testing.T) {
+	tests := []struct {
+		name      string
+		addresses []string
+		opts      []Option
+		want      map[string]int
+		wantCalls map[string]int
+	}{
+		{
+			name:      "хук не меняет набор запросов",
+			addresses: []string{"addr1", "addr1", "addr2"},
+			want:      map[string]int{"addr1": 2},
+			wantCalls: map[string]int{"addr1": 2, "addr2": 1},
+		},
+		{
+			name:      "хук вместе с дедупликацией",
+			addresses: []string{"addr1", "addr2", "addr1", "addr2", "addr1"},
+			opts:      []Option{WithDedup()},
+			want:      map[string]int{"addr1": 3, "addr2": 2},
+			wantCalls: map[string]int{"addr1": 1, "addr2": 1},
+		},
+		{
+			name:      "без дубликатов хук молчит",
+			addresses: []string{"addr1", "addr2"},
+			want:      map[string]int{},
+			wantCalls: map[string]int{"addr1": 1, "addr2": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockGetter(map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("err")}},
+				"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+			})
+			getter := newCountingGetter(mock)
+
+			got := map[string]int{}
+			opts := append([]Option{WithDuplicateAddressHook(func(address string, count int) {
+				if _, seen := got[address]; seen {
+					t.Errorf("hook called twice for %s", address)
+				}
+				got[address] = count
+			})}, tt.opts...)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			if _, err := Get(ctx, getter, tt.addresses, "key1", opts...); err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+
+			if !maps.Equal(got, tt.want) {
+				t.Fatalf("hook reported %v, want %v", got, tt.want)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithRetryableFunc(t *testing.T) {
+	errNotFound := errors.New("key not found")
+	retryable := func(err error) bool {
+		return !errors.Is(err, errNotFound)
+	}
+
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		opts      []Option
+		wantValue string
+		wantErrIs error
+		wantCalls map[string]int
+	}{
+		{
+			name: "терминальная ошибка останавливает опрос",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errNotFound}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			opts:      []Option{WithRetryableFunc(retryable), WithHedgeDelay(100 * time.Millisecond)},
+			wantErrIs: errNotFound,
+			wantCalls: map[string]int{"addr1": 1, "addr2": 0},
+		},
+		{
+			name: "терминальная ошибка не повторяется",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errNotFound}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			opts:      []Option{WithRetryableFunc(retryable), WithMaxConcurrency(1), WithRetry(3)},
+			wantErrIs: errNotFound,
+			wantCalls: map[string]int{"addr1": 1, "addr2": 0},
+		},
+		{
+			name: "повторяемая ошибка переходит к следующему адресу",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			opts:      []Option{WithRetryableFunc(retryable), WithHedgeDelay(100 * time.Millisecond)},
+			wantValue: "value2",
+			wantCalls: map[string]int{"addr1": 1, "addr2": 1},
+		},
+		{
+			name: "терминальная ошибка не ждёт медленный адрес",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errNotFound, Delay: 10 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2", Delay: time.Second}},
+			},
+			opts:      []Option{WithRetryableFunc(retryable)},
+			wantErrIs: errNotFound,
+			wantCalls: map[string]int{"addr1": 1, "addr2": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(tt.responses))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+
+			got, err := Get(ctx, getter, []string{"addr1", "addr2"}, "key1", tt.opts...)
+
+			if !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("Get() error = %v, want %v", err, tt.wantErrIs)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("Get() = %q, want %q", got, tt.wantValue)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithFailFastOn(t *testing.T) {
+	errUnauthorized := errors.New("unauthorized")
+
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: fmt.Errorf("addr1 says: %w", errUnauthorized), Delay: 10 * time.Millisecond}},
+		"addr2": {"key1": {Value: "value2", Delay: 200 * time.Millisecond}},
+		"addr3": {"key1": {Value: "value3", Delay: 200 * time.Millisecond}},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	got, err := Get(ctx, mock, []string{"addr1", "addr2", "addr3"}, "key1", WithFailFastOn(errors.ErrUnsupported, errUnauthorized))
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, errUnauthorized) || got != "" {
+		t.Fatalf("Get() = (%q, %v), want errors.Is(err, %v) == true", got, err, errUnauthorized)
+	}
+
+	if elapsed > 100*time.Millisecond {
+		t.Fatalf("Get() took %v, want it to return before the slower addresses respond", elapsed)
+	}
+}
+
+func TestGetWithAbortOnFirstError(t *testing.T) {
+	errBroken := errors.New("broken replica")
+
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errBroken, Delay: 10 * time.Millisecond}},
+		"addr2": {"key1": {Value: "value2", Delay: 200 * time.Millisecond}},
+	})
+	getter := newCountingGetter(mock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	got, err := Get(ctx, getter, []string{"addr1", "addr2"}, "key1", WithAbortOnFirstError())
+	elapsed := time.Since(start)
+
+	var addrErr *AddressError
+	if !errors.As(err, &addrErr) || addrErr.Address != "addr1" || !errors.Is(err, errBroken) || got != "" {
+		t.Fatalf("Get() = (%q, %v), want addr1's %v", got, err, errBroken)
+	}
+
+	if elapsed > 100*time.Millisecond {
+		t.Fatalf("Get() took %v, want it to abort before addr2 responds", elapsed)
+	}
+
+	got, err = Get(ctx, mock, []string{"addr1", "addr2"}, "key1")
+	if err != nil || got != "value2" {
+		t.Fatalf("Get() without the option = (%q, %v), want (%q, nil)", got, err, "value2")
+	}
+}
+
+func TestGetWithSequential(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection error"), Delay: 20 * time.Millisecond}},
+		"addr2": {"key1": {Value: "value2", Delay: 10 * time.Millisecond}},
+		"addr3": {"key1": {Value: "value3"}},
+		"hang":  {"key1": {Value: "late", Delay: time.Second}},
+	})
+
+	t.Run("адреса по очереди до первого успеха", func(t *testing.T) {
+		counting := newCountingGetter(mock)
+		getter := &orderRecordingGetter{Getter: counting}
+
+		got, err := Get(context.Background(), getter, []string{"addr1", "addr2", "addr3"}, "key1",
+			WithSequential(), WithHedgeDelay(time.Millisecond), WithMaxConcurrency(3))
+		if err != nil || got != "value2" {
+			t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value2")
+		}
+
+		if want := []string{"addr1", "addr2"}; !slices.Equal(getter.order, want) {
+			t.Fatalf("queried %v, want %v", getter.order, want)
+		}
+
+		if n := counting.MaxInFlight(); n != 1 {
+			t.Fatalf("max in flight = %d, want 1", n)
+		}
+	})
+
+	t.Run("отмена прерывает текущую попытку", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := Get(ctx, mock, []string{"hang", "addr3"}, "key1", WithSequential())
+		if !errors.Is(err, context.DeadlineExceeded) || time.Since(start) > 200*time.Millisecond {
+			t.Fatalf("Get() error = %v after %v, want the deadline to abort the attempt", err, time.Since(start))
+		}
+	})
+}
+
+func TestGetWithRejectEmpty(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		opts      []Option
+		wantValue string
+		wantErrIs error
+	}{
+		{
+			name: "пустое значение пропускается",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: ""}},
+				"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+			},
+			opts:      []Option{WithRejectEmpty()},
+			wantValue: "value2",
+		},
+		{
+			name: "все значения пустые или ошибки",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: ""}},
+				"addr2": {"key1": {Error: errors.New("connection error")}},
+			},
+			opts:      []Option{WithRejectEmpty()},
+			wantErrIs: ErrEmptyValue,
+		},
+		{
+			name: "без опции пустое значение — законный ответ",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: ""}},
+				"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := Get(ctx, NewMockGetter(tt.responses), []string{"addr1", "addr2"}, "key1", tt.opts...)
+
+			if got != tt.wantValue || !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("Get() = (%q, %v), want (%q, %v)", got, err, tt.wantValue, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestGetWithAcceptFunc(t *testing.T) {
+	notTombstone := func(value string) bool { return value != "<tombstone>" }
+
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		opts      []Option
+		wantValue string
+		wantErrIs error
+	}{
+		{
+			name: "надгробие первого адреса пропускается",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "<tombstone>"}},
+				"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+			},
+			opts:      []Option{WithAcceptFunc(notTombstone)},
+			wantValue: "value2",
+		},
+		{
+			name: "все успешные ответы отклонены",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "<tombstone>"}},
+				"addr2": {"key1": {Value: "<tombstone>", Delay: 20 * time.Millisecond}},
+			},
+			opts:      []Option{WithAcceptFunc(notTombstone)},
+			wantErrIs: ErrNoAcceptableValue,
+		},
+		{
+			name: "без опции надгробие — обычное значение",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "<tombstone>"}},
+				"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+			},
+			wantValue: "<tombstone>",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := Get(ctx, NewMockGetter(tt.responses), []string{"addr1", "addr2"}, "key1", tt.opts...)
+
+			if got != tt.wantValue || !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("Get() = (%q, %v), want (%q, %v)", got, err, tt.wantValue, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestGetWithMinResponses(t *testing.T) {
+	errBroken := errors.New("broken replica")
+
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		opts      []Option
+		wantValue string
+		wantErrIs error
+	}{
+		{
+			name: "медленный успех не вытесняется быстрыми отказами",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errBroken}},
+				"addr2": {"key1": {Error: errBroken, Delay: 5 * time.Millisecond}},
+				"addr3": {"key1": {Value: "value3", Delay: 30 * time.Millisecond}},
+			},
+			opts:      []Option{WithAbortOnFirstError(), WithMinResponses(3)},
+			wantValue: "value3",
+		},
+		{
+			name: "без порога первый отказ обрывает всё",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errBroken}},
+				"addr2": {"key1": {Error: errBroken, Delay: 5 * time.Millisecond}},
+				"addr3": {"key1": {Value: "value3", Delay: 30 * time.Millisecond}},
+			},
+			opts:      []Option{WithAbortOnFirstError()},
+			wantErrIs: errBroken,
+		},
+		{
+			name: "порог больше числа адресов — ждём все",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errBroken}},
+				"addr2": {"key1": {Error: errors.New("connection error"), Delay: 5 * time.Millisecond}},
+				"addr3": {"key1": {Error: errors.New("connection error"), Delay: 10 * time.Millisecond}},
+			},
+			opts:      []Option{WithFailFastOn(errBroken), WithMinResponses(10)},
+			wantErrIs: errBroken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(tt.responses))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := Get(ctx, getter, []string{"addr1", "addr2", "addr3"}, "key1", tt.opts...)
+
+			if got != tt.wantValue || !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("Get() = (%q, %v), want (%q, %v)", got, err, tt.wantValue, tt.wantErrIs)
+			}
+
+			var addrErr *AddressError
+			if tt.wantErrIs != nil && (!errors.As(err, &addrErr) || addrErr.Address != "addr1") {
+				t.Fatalf("Get() error = %v, want the terminal error of addr1", err)
+			}
+		})
+	}
+}
+
+// startRecordingGetter records how long after begin each address was called.
+type startRecordingGetter struct {
+	Getter
+	begin time.Time
+
+	mu     sync.Mutex
+	starts map[string]time.Duration
+}
+
+func (s *startRecordingGetter) Get(ctx context.Context, address, key string) (string, error) {
+	s.mu.Lock()
+	s.starts[address] = time.Since(s.begin)
+	s.mu.Unlock()
+
+	return s.Getter.Get(ctx, address, key)
+}
+
+func TestGetWithMaxLaunchRate(t *testing.T) {
+	const interval = 40 * time.Millisecond
+
+	errConn := errors.New("connection error")
+	addresses := []string{"addr0", "addr1", "addr2", "addr3"}
+	responses := map[string]map[string]Response{}
+	for _, address := range addresses {
+		responses[address] = map[string]Response{"key1": {Error: errConn, Delay: 5 * time.Millisecond}}
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "все сразу", opts: []Option{WithMaxLaunchRate(int(time.Second / interval))}},
+		{name: "с частым хеджированием", opts: []Option{WithMaxLaunchRate(int(time.Second / interval)), WithHedgeDelay(time.Millisecond)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := &startRecordingGetter{Getter: NewMockGetter(responses), starts: map[string]time.Duration{}}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			getter.begin = time.Now()
+			if _, err := Get(ctx, getter, addresses, "key1", tt.opts...); !errors.Is(err, errConn) {
+				t.Fatalf("Get() error = %v, want %v", err, errConn)
+			}
+
+			if d := getter.starts["addr0"]; d >= interval/2 {
+				t.Errorf("first address started after %v, want immediately", d)
+			}
+
+			for i, address := range addresses[1:] {
+				if want := time.Duration(i+1) * interval; getter.starts[address] < want {
+					t.Errorf("%s started after %v, want no earlier than %v", address, getter.starts[address], want)
+				}
+			}
+		})
+	}
+}
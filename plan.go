@@ -0,0 +1,148 @@
+package main
+
+import "time"
+
+// ExecutionPlan describes what Get would do with some addresses and options,
+// as worked out by Plan without querying anything.
+type ExecutionPlan struct {
+	// Attempts lists the addresses that would be queried, in the order they
+	// would be started.
+	Attempts []PlannedAttempt
+
+	// MaxAttempts is how many times each address would be tried at most.
+	MaxAttempts int
+
+	// Err is the error Get would fail with before querying any address,
+	// such as ErrNoHealthyAddresses.
+	Err error
+}
+
+// PlannedAttempt is one address of an ExecutionPlan.
+type PlannedAttempt struct {
+	Address string
+
+	// Tier is the position, among the tiers that have addresses, of the
+	// tier the address would be raced in. It is always zero for Plan.
+	Tier int
+
+	// Start is how long after its tier started the address would be
+	// started, following the hedge delay, launch rate and start delays, if
+	// no earlier address answered by then. Random start jitter is not
+	// included.
+	Start time.Duration
+
+	// AfterSlot tells that the address would only be started once an
+	// earlier one finished, because of WithMaxConcurrency or
+	// WithSequential. Start then counts from that moment.
+	AfterSlot bool
+
+	// Timeout bounds every call to the address; zero means unbounded.
+	// Budgets derived from the context deadline come on top of it.
+	Timeout time.Duration
+}
+
+// Plan returns what Get over addresses with opts would do: which addresses
+// it would query after resolving, deduplicating, filtering and truncating
+// them, when it would start each and with what timeout. Health checks and
+// circuit breakers are consulted as they are at the time of the call. No
+// getter is called, and neither is the hook of WithDuplicateAddressHook.
+func Plan(addresses []string, key string, opts ...Option) ExecutionPlan {
+	return plan([]tier{{addresses: addresses}}, key, newConfig(opts))
+}
+
+// PlanEndpoints is Plan for GetEndpoints.
+func PlanEndpoints(endpoints []Endpoint, key string, opts ...Option) ExecutionPlan {
+	cfg := newConfig(opts)
+	return plan(endpointTiers(endpoints, cfg.region, cfg.stagger()), key, cfg)
+}
+
+func plan(tiers []tier, key string, cfg config) ExecutionPlan {
+	if err := validateKey(key); err != nil {
+		return ExecutionPlan{Err: err}
+	}
+
+	cfg.onDuplicate = nil
+	tiers, cfg = resolveTiers(tiers, key, cfg)
+	p := ExecutionPlan{MaxAttempts: max(cfg.maxAttempts, 1)}
+	for _, t := range tiers {
+		addresses := t.addresses
+		if len(addresses) == 0 {
+			continue
+		}
+
+		addresses, err := prepare(addresses, key, cfg)
+		if err != nil {
+			p.Err = err
+			continue
+		}
+
+		if len(addresses) == 0 {
+			continue
+		}
+
+		tcfg := cfg
+		if t.startDelays != nil {
+			tcfg.startDelays = t.startDelays
+		}
+
+		tierIndex := 0
+		if len(p.Attempts) > 0 {
+			tierIndex = p.Attempts[len(p.Attempts)-1].Tier + 1
+		}
+
+		var slot time.Duration
+		for i, address := range addresses {
+			start, afterSlot := tcfg.launchOffset(i, len(addresses))
+			if tcfg.launchInterval > 0 && !afterSlot {
+				if i > 0 {
+					start = max(start, slot+tcfg.launchInterval)
+				}
+				slot = start
+			}
+
+			start += tcfg.startDelays[address]
+
+			p.Attempts = append(p.Attempts, PlannedAttempt{
+				Address:   address,
+				Tier:      tierIndex,
+				Start:     start,
+				AfterSlot: afterSlot,
+				Timeout:   tcfg.timeoutFor(address),
+			})
+		}
+	}
+
+	if len(p.Attempts) > 0 {
+		p.Err = nil
+	}
+
+	return p
+}
+
+// launchOffset tells when run would launch the i-th of n addresses: after
+// how long from the start of the race, or, when afterSlot is set, only once
+// an earlier attempt finished.
+func (c config) launchOffset(i, n int) (offset time.Duration, afterSlot bool) {
+	limit := c.concurrencyLimit()
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+
+	hedgeDelay := c.hedgeDelay
+	if c.latencies != nil {
+		if d := c.latencies.HedgeDelay(); d > 0 {
+			hedgeDelay = d
+		}
+	}
+
+	switch {
+	case i >= limit:
+		return 0, true
+	case hedgeDelay <= 0 || c.sequential:
+		return 0, false
+	case c.hedgeAll:
+		return min(time.Duration(i), 1) * hedgeDelay, false
+	default:
+		return time.Duration(i) * hedgeDelay, false
+	}
+}
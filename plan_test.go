@@ -0,0 +1,156 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPlan(t *testing.T) {
+	tests := []struct {
+		name          string
+		addresses     []string
+		opts          []Option
+		wantAddresses []string
+		wantStarts    []time.Duration
+		wantAfterSlot []bool
+	}{
+		{
+			name:          "без опций — все сразу",
+			addresses:     []string{"a", "b", "c"},
+			wantAddresses: []string{"a", "b", "c"},
+			wantStarts:    []time.Duration{0, 0, 0},
+			wantAfterSlot: []bool{false, false, false},
+		},
+		{
+			name:          "дубликаты схлопываются",
+			addresses:     []string{"a", "b", "a", "c", "b"},
+			opts:          []Option{WithDedup()},
+			wantAddresses: []string{"a", "b", "c"},
+			wantStarts:    []time.Duration{0, 0, 0},
+			wantAfterSlot: []bool{false, false, false},
+		},
+		{
+			name:          "лишние адреса отбрасываются",
+			addresses:     []string{"a", "b", "c", "d"},
+			opts:          []Option{WithMaxAddresses(2)},
+			wantAddresses: []string{"a", "b"},
+			wantStarts:    []time.Duration{0, 0},
+			wantAfterSlot: []bool{false, false},
+		},
+		{
+			name:          "хеджирование разносит старты",
+			addresses:     []string{"a", "b", "c"},
+			opts:          []Option{WithHedgeDelay(10 * time.Millisecond)},
+			wantAddresses: []string{"a", "b", "c"},
+			wantStarts:    []time.Duration{0, 10 * time.Millisecond, 20 * time.Millisecond},
+			wantAfterSlot: []bool{false, false, false},
+		},
+		{
+			name:          "ограничение параллелизма — остальные ждут слота",
+			addresses:     []string{"a", "b", "c"},
+			opts:          []Option{WithMaxConcurrency(2)},
+			wantAddresses: []string{"a", "b", "c"},
+			wantStarts:    []time.Duration{0, 0, 0},
+			wantAfterSlot: []bool{false, false, true},
+		},
+		{
+			name:          "последовательно — каждый после предыдущего",
+			addresses:     []string{"a", "b"},
+			opts:          []Option{WithSequential(), WithHedgeDelay(10 * time.Millisecond)},
+			wantAddresses: []string{"a", "b"},
+			wantStarts:    []time.Duration{0, 0},
+			wantAfterSlot: []bool{false, true},
+		},
+		{
+			name:          "частота запусков растягивает хеджирование",
+			addresses:     []string{"a", "b", "c"},
+			opts:          []Option{WithHedgeDelay(time.Millisecond), WithMaxLaunchRate(100)},
+			wantAddresses: []string{"a", "b", "c"},
+			wantStarts:    []time.Duration{0, 10 * time.Millisecond, 20 * time.Millisecond},
+			wantAfterSlot: []bool{false, false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := Plan(tt.addresses, "key1", tt.opts...)
+			if p.Err != nil {
+				t.Fatalf("Plan().Err = %v, want nil", p.Err)
+			}
+
+			var addresses []string
+			var starts []time.Duration
+			var afterSlot []bool
+			for _, a := range p.Attempts {
+				addresses = append(addresses, a.Address)
+				starts = append(starts, a.Start)
+				afterSlot = append(afterSlot, a.AfterSlot)
+			}
+
+			if !slices.Equal(addresses, tt.wantAddresses) {
+				t.Errorf("addresses = %v, want %v", addresses, tt.wantAddresses)
+			}
+
+			if !slices.Equal(starts, tt.wantStarts) {
+				t.Errorf("starts = %v, want %v", starts, tt.wantStarts)
+			}
+
+			if !slices.Equal(afterSlot, tt.wantAfterSlot) {
+				t.Errorf("afterSlot = %v, want %v", afterSlot, tt.wantAfterSlot)
+			}
+		})
+	}
+
+	t.Run("таймауты по адресам", func(t *testing.T) {
+		p := Plan([]string{"a", "b"}, "key1",
+			WithAttemptTimeout(time.Second),
+			WithPerAddressTimeout(map[string]time.Duration{"b": 50 * time.Millisecond}),
+			WithRetry(3),
+		)
+
+		if p.MaxAttempts != 3 {
+			t.Errorf("MaxAttempts = %d, want 3", p.MaxAttempts)
+		}
+
+		if p.Attempts[0].Timeout != time.Second || p.Attempts[1].Timeout != 50*time.Millisecond {
+			t.Errorf("timeouts = %v, %v, want 1s, 50ms", p.Attempts[0].Timeout, p.Attempts[1].Timeout)
+		}
+	})
+
+	t.Run("пустой ключ", func(t *testing.T) {
+		if p := Plan([]string{"a"}, ""); !errors.Is(p.Err, ErrEmptyKey) || len(p.Attempts) != 0 {
+			t.Errorf("Plan() = %+v, want ErrEmptyKey", p)
+		}
+	})
+
+	t.Run("нет здоровых адресов", func(t *testing.T) {
+		p := Plan([]string{"a"}, "key1", WithHealthChecker(func(string) bool { return false }))
+		if !errors.Is(p.Err, ErrNoHealthyAddresses) {
+			t.Errorf("Plan().Err = %v, want ErrNoHealthyAddresses", p.Err)
+		}
+	})
+}
+
+func TestPlanEndpoints(t *testing.T) {
+	endpoints := []Endpoint{
+		{Address: "remote", Region: "us"},
+		{Address: "local", Region: "eu"},
+		{Address: "backup", Tier: 1, Region: "eu"},
+	}
+
+	p := PlanEndpoints(endpoints, "key1", WithPreferredRegion("eu"))
+	if p.Err != nil {
+		t.Fatalf("PlanEndpoints().Err = %v, want nil", p.Err)
+	}
+
+	want := []PlannedAttempt{
+		{Address: "local", Tier: 0},
+		{Address: "remote", Tier: 1},
+		{Address: "backup", Tier: 2},
+	}
+	if !slices.Equal(p.Attempts, want) {
+		t.Errorf("Attempts = %+v, want %+v", p.Attempts, want)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrNoQuorum              = errors.New("no value reached quorum")
+	ErrInsufficientAgreement = errors.New("not enough addresses agreed on a value")
+)
+
+// GetQuorum queries every address and returns the value reported by a strict
+// majority of them. Failed addresses stay in the denominator: they are
+// treated as votes against every value, because a replica that did not
+// answer may well hold a different one. Ties and splits return ErrNoQuorum.
+func GetQuorum(ctx context.Context, getter Getter, addresses []string, key string) (string, error) {
+	value, _, err := GetQuorumWithDissent(ctx, getter, addresses, key)
+	return value, err
+}
+
+// GetQuorumWithDissent is GetQuorum that also returns, in input order, the
+// addresses that failed or reported a value other than the quorum one, so
+// that they can be repaired. Dissenters are only returned along with a
+// quorum value.
+func GetQuorumWithDissent(ctx context.Context, getter Getter, addresses []string, key string) (value string, dissenters []string, err error) {
+	results, err := GetAll(ctx, getter, addresses, key)
+	if err != nil || len(results) == 0 {
+		return "", nil, err
+	}
+
+	votes := make(map[string]int)
+	for _, r := range results {
+		if r.Err == nil {
+			votes[r.Value]++
+		}
+	}
+
+	for value, n := range votes {
+		if n <= len(results)/2 {
+			continue
+		}
+
+		for _, r := range results {
+			if r.Err != nil || r.Value != value {
+				dissenters = append(dissenters, r.Address)
+			}
+		}
+
+		return value, dissenters, nil
+	}
+
+	return "", nil, ErrNoQuorum
+}
+
+// GetQuorumEarly is GetQuorum without waiting for every address: it tallies
+// the answers as they arrive and returns as soon as a value is reported by a
+// strict majority of the addresses, cancelling the remaining attempts. It
+// returns ErrNoQuorum as soon as the outstanding addresses can no longer
+// give any value a majority.
+func GetQuorumEarly(ctx context.Context, getter Getter, addresses []string, key string) (string, error) {
+	return agree(ctx, getter, addresses, key, len(addresses)/2+1, ErrNoQuorum)
+}
+
+// GetNWins queries every address and returns the first value reported by n
+// of them, cancelling the remaining attempts as soon as that happens. It
+// returns ErrInsufficientAgreement once the outstanding addresses can no
+// longer lift any value to n votes. An n of 1 or less is the same as Get.
+func GetNWins(ctx context.Context, getter Getter, addresses []string, key string, n int) (string, error) {
+	if n <= 1 {
+		return Get(ctx, getter, addresses, key)
+	}
+
+	return agree(ctx, getter, addresses, key, n, ErrInsufficientAgreement)
+}
+
+// agree returns the first value reported by n addresses, or errNoAgreement
+// once no value can get there any more.
+func agree(ctx context.Context, getter Getter, addresses []string, key string, n int, errNoAgreement error) (value string, err error) {
+	if err := validate[string](getter, key); err != nil {
+		return "", err
+	}
+
+	if n > len(addresses) {
+		return "", errNoAgreement
+	}
+
+	// The attempts still running are told whether they lost to a winner.
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer func() {
+		if err == nil {
+			cancel(ErrWinnerFound)
+		} else {
+			cancel(nil)
+		}
+	}()
+
+	votes := make(map[string]int)
+	best, pending := 0, len(addresses)
+	for r := range GetStream(ctx, getter, addresses, key) {
+		pending--
+		if r.Err == nil {
+			votes[r.Value]++
+			if votes[r.Value] == n {
+				return r.Value, nil
+			}
+
+			best = max(best, votes[r.Value])
+		}
+
+		if best+pending < n {
+			return "", errNoAgreement
+		}
+	}
+
+	return "", canceled(ctx)
+}
+
+// GetWithConflicts queries every address and returns the first successful
+// value, like Get, together with every distinct value returned and the
+// number of addresses that returned it, so that replica divergence can be
+// spotted. Unlike Get it waits for all the addresses to answer.
+func GetWithConflicts(ctx context.Context, getter Getter, addresses []string, key string) (value string, conflicts map[string]int, err error) {
+	results, err := GetAll(ctx, getter, addresses, key)
+	if err != nil || len(results) == 0 {
+		return "", nil, err
+	}
+
+	// The first success is the one that took the least time, as all the
+	// addresses were queried at once.
+	conflicts = make(map[string]int)
+	first := -1
+	for i, r := range results {
+		if r.Err != nil {
+			continue
+		}
+
+		conflicts[r.Value]++
+		if first < 0 || r.Latency < results[first].Latency {
+			first = i
+		}
+	}
+
+	return results[first].Value, conflicts, nil
+}
@@ -0,0 +1,382 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetQuorum(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		addresses []string
+		wantValue string
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name: "чистое большинство",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Value: "b"}},
+				"addr3": {"key1": {Value: "a"}},
+			},
+			addresses: []string{"addr1", "addr2", "addr3"},
+			wantValue: "a",
+		},
+		{
+			name: "ничья",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Value: "b"}},
+				"addr3": {"key1": {Value: "a"}},
+				"addr4": {"key1": {Value: "b"}},
+			},
+			addresses: []string{"addr1", "addr2", "addr3", "addr4"},
+			wantErr:   true,
+			wantErrIs: ErrNoQuorum,
+		},
+		{
+			name: "разброс без большинства",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Value: "b"}},
+				"addr3": {"key1": {Value: "c"}},
+			},
+			addresses: []string{"addr1", "addr2", "addr3"},
+			wantErr:   true,
+			wantErrIs: ErrNoQuorum,
+		},
+		{
+			name: "упавшие адреса остаются в знаменателе",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Error: errors.New("connection error")}},
+				"addr3": {"key1": {Error: errors.New("connection error")}},
+			},
+			addresses: []string{"addr1", "addr2", "addr3"},
+			wantErr:   true,
+			wantErrIs: ErrNoQuorum,
+		},
+		{
+			name: "большинство несмотря на упавший адрес",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Error: errors.New("connection error")}},
+				"addr3": {"key1": {Value: "a"}},
+			},
+			addresses: []string{"addr1", "addr2", "addr3"},
+			wantValue: "a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetQuorum(ctx, NewMockGetter(tt.responses), tt.addresses, "key1")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetQuorum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("GetQuorum() error = %v, want errors.Is(err, %v) == true", err, tt.wantErrIs)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetQuorum() = %q, want %q", got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestGetQuorumWithDissent(t *testing.T) {
+	addresses := []string{"addr1", "addr2", "addr3"}
+	tests := []struct {
+		name           string
+		responses      map[string]map[string]Response
+		wantValue      string
+		wantDissenters []string
+		wantErrIs      error
+	}{
+		{
+			name: "один адрес расходится",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Value: "stale"}},
+				"addr3": {"key1": {Value: "a"}},
+			},
+			wantValue:      "a",
+			wantDissenters: []string{"addr2"},
+		},
+		{
+			name: "адрес без значения тоже расходится",
+			responses: map[string]map[string]Response{
+				"addr1": {},
+				"addr2": {"key1": {Value: "a"}},
+				"addr3": {"key1": {Value: "a"}},
+			},
+			wantValue:      "a",
+			wantDissenters: []string{"addr1"},
+		},
+		{
+			name: "все согласны",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Value: "a"}},
+				"addr3": {"key1": {Value: "a"}},
+			},
+			wantValue: "a",
+		},
+		{
+			name: "кворума нет",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Value: "b"}},
+				"addr3": {},
+			},
+			wantErrIs: ErrNoQuorum,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, dissenters, err := GetQuorumWithDissent(ctx, NewMockGetter(tt.responses), addresses, "key1")
+			if got != tt.wantValue || !slices.Equal(dissenters, tt.wantDissenters) {
+				t.Fatalf("GetQuorumWithDissent() = (%q, %v), want (%q, %v)", got, dissenters, tt.wantValue, tt.wantDissenters)
+			}
+
+			if tt.wantErrIs == nil && err != nil || tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("GetQuorumWithDissent() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestGetQuorumEarly(t *testing.T) {
+	addresses := []string{"addr1", "addr2", "addr3", "addr4", "addr5"}
+	tests := []struct {
+		name       string
+		responses  map[string]map[string]Response
+		wantValue  string
+		wantErrIs  error
+		wantBefore time.Duration
+	}{
+		{
+			name: "большинство набрано — медленные не ждём",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Value: "a", Delay: 10 * time.Millisecond}},
+				"addr3": {"key1": {Value: "a", Delay: 20 * time.Millisecond}},
+				"addr4": {"key1": {Value: "a", Delay: time.Second}},
+				"addr5": {"key1": {Value: "b", Delay: time.Second}},
+			},
+			wantValue:  "a",
+			wantBefore: 500 * time.Millisecond,
+		},
+		{
+			name: "большинство недостижимо — сразу ErrNoQuorum",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Value: "b"}},
+				"addr3": {"key1": {Error: errors.New("connection error")}},
+				"addr4": {"key1": {Value: "c", Delay: 10 * time.Millisecond}},
+				"addr5": {"key1": {Value: "a", Delay: time.Second}},
+			},
+			wantErrIs:  ErrNoQuorum,
+			wantBefore: 500 * time.Millisecond,
+		},
+		{
+			name: "ошибки считаются голосами против",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Value: "a"}},
+				"addr3": {"key1": {Error: errors.New("connection error")}},
+				"addr4": {"key1": {Error: errors.New("connection error")}},
+				"addr5": {"key1": {Error: errors.New("connection error"), Delay: 10 * time.Millisecond}},
+			},
+			wantErrIs:  ErrNoQuorum,
+			wantBefore: 500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			start := time.Now()
+			got, err := GetQuorumEarly(ctx, NewMockGetter(tt.responses), addresses, "key1")
+			if elapsed := time.Since(start); elapsed > tt.wantBefore {
+				t.Fatalf("GetQuorumEarly() took %v, want less than %v", elapsed, tt.wantBefore)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetQuorumEarly() = %q, want %q", got, tt.wantValue)
+			}
+
+			if tt.wantErrIs == nil && err != nil || tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("GetQuorumEarly() error = %v, want %v", err, tt.wantErrIs)
+			}
+		})
+	}
+}
+
+func TestGetNWins(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		n         int
+		wantValue string
+		wantErrIs error
+		wantErr   bool
+	}{
+		{
+			name: "два из трёх согласны",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a", Delay: 10 * time.Millisecond}},
+				"addr2": {"key1": {Value: "b"}},
+				"addr3": {"key1": {Value: "a", Delay: 20 * time.Millisecond}},
+			},
+			n:         2,
+			wantValue: "a",
+		},
+		{
+			name: "n равно 1 — как Get",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a", Delay: 20 * time.Millisecond}},
+				"addr2": {"key1": {Value: "b"}},
+				"addr3": {"key1": {Value: "a", Delay: 20 * time.Millisecond}},
+			},
+			n:         1,
+			wantValue: "b",
+		},
+		{
+			name: "согласия трёх не набрать",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Value: "b"}},
+				"addr3": {"key1": {Value: "a"}},
+			},
+			n:         3,
+			wantErr:   true,
+			wantErrIs: ErrInsufficientAgreement,
+		},
+		{
+			name: "n больше числа адресов",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Value: "a"}},
+				"addr3": {"key1": {Value: "a"}},
+			},
+			n:         4,
+			wantErr:   true,
+			wantErrIs: ErrInsufficientAgreement,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetNWins(ctx, NewMockGetter(tt.responses), []string{"addr1", "addr2", "addr3"}, "key1", tt.n)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetNWins() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("GetNWins() error = %v, want errors.Is(err, %v) == true", err, tt.wantErrIs)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetNWins() = %q, want %q", got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestGetWithConflicts(t *testing.T) {
+	tests := []struct {
+		name          string
+		responses     map[string]map[string]Response
+		wantValue     string
+		wantConflicts map[string]int
+		wantErr       bool
+	}{
+		{
+			name: "два различных значения",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a", Delay: 20 * time.Millisecond}},
+				"addr2": {"key1": {Value: "b"}},
+				"addr3": {"key1": {Value: "a", Delay: 10 * time.Millisecond}},
+			},
+			wantValue:     "b",
+			wantConflicts: map[string]int{"a": 2, "b": 1},
+		},
+		{
+			name: "упавшие адреса не учитываются",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "a"}},
+				"addr2": {"key1": {Error: errors.New("connection error")}},
+				"addr3": {"key1": {Value: "a", Delay: 10 * time.Millisecond}},
+			},
+			wantValue:     "a",
+			wantConflicts: map[string]int{"a": 2},
+		},
+		{
+			name: "все адреса падают",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("error 1")}},
+				"addr2": {"key1": {Error: errors.New("error 2")}},
+				"addr3": {"key1": {Error: errors.New("error 3")}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, conflicts, err := GetWithConflicts(ctx, NewMockGetter(tt.responses), []string{"addr1", "addr2", "addr3"}, "key1")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetWithConflicts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.wantValue || !maps.Equal(conflicts, tt.wantConflicts) {
+				t.Fatalf("GetWithConflicts() = (%q, %v), want (%q, %v)", got, conflicts, tt.wantValue, tt.wantConflicts)
+			}
+		})
+	}
+}
+
+func TestGetQuorumEarlyCancelsLosersWithWinnerFound(t *testing.T) {
+	getter := causeRecordingGetter{causes: make(chan error, 1)}
+
+	got, err := GetQuorumEarly(context.Background(), getter, []string{"fast", "fast", "slow"}, "key1")
+	if err != nil || got != "value-fast" {
+		t.Fatalf("GetQuorumEarly() = (%q, %v), want (%q, nil)", got, err, "value-fast")
+	}
+
+	select {
+	case cause := <-getter.causes:
+		if !errors.Is(cause, ErrWinnerFound) {
+			t.Fatalf("loser saw cause %v, want %v", cause, ErrWinnerFound)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("loser not cancelled")
+	}
+}
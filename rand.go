@@ -0,0 +1,53 @@
+package main
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// WithRand sets the random source behind every random choice Get makes, such
+// as the order of GetShuffled and the backoff jitter, so tests can make them
+// reproducible. Without it, or with a nil r, a source shared by all calls
+// and seeded once per process is used.
+func WithRand(r *rand.Rand) Option {
+	return func(c *config) {
+		c.rand = nil
+		if r != nil {
+			c.rand = &randSource{r: r}
+		}
+	}
+}
+
+// randSource serializes the use of a *rand.Rand, which is not safe for
+// concurrent use, by the attempts running in parallel.
+type randSource struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+var defaultRand = &randSource{r: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))}
+
+// random returns the configured random source, or the shared default.
+func (c config) random() *randSource {
+	if c.rand != nil {
+		return c.rand
+	}
+
+	return defaultRand
+}
+
+func (s *randSource) shuffle(n int, swap func(i, j int)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.r.Shuffle(n, swap)
+}
+
+// duration returns a uniform duration in [0, d).
+func (s *randSource) duration(d time.Duration) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return time.Duration(s.r.Int64N(int64(d)))
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type orderRecordingGetter struct {
+	Getter
+
+	mu    sync.Mutex
+	order []string
+}
+
+func (o *orderRecordingGetter) Get(ctx context.Context, address, key string) (string, error) {
+	o.mu.Lock()
+	o.order = append(o.order, address)
+	o.mu.Unlock()
+
+	return o.Getter.Get(ctx, address, key)
+}
+
+func TestWithRandReproducible(t *testing.T) {
+	addresses := []string{"addr1", "addr2", "addr3", "addr4", "addr5"}
+
+	t.Run("порядок GetShuffled", func(t *testing.T) {
+		var orders [2][]string
+		for i := range orders {
+			getter := &orderRecordingGetter{Getter: NewMockGetter(nil)}
+
+			_, err := GetShuffled(context.Background(), getter, addresses, "key1",
+				WithMaxConcurrency(1), WithRand(rand.New(rand.NewPCG(1, 2))))
+			if err == nil {
+				t.Fatal("GetShuffled() error = nil, want every address to fail")
+			}
+
+			orders[i] = getter.order
+		}
+
+		want := []string{"addr2", "addr5", "addr3", "addr1", "addr4"}
+		for i, order := range orders {
+			if !slices.Equal(order, want) {
+				t.Fatalf("run %d queried %v, want %v", i+1, order, want)
+			}
+		}
+	})
+
+	t.Run("задержки с разбросом", func(t *testing.T) {
+		var delays [2][]time.Duration
+		for i := range delays {
+			cfg := newConfig([]Option{
+				WithBackoff(10*time.Millisecond, time.Second),
+				WithJitter(),
+				WithRand(rand.New(rand.NewPCG(3, 4))),
+			})
+
+			for retry := 1; retry <= 5; retry++ {
+				delays[i] = append(delays[i], cfg.backoff(retry))
+			}
+		}
+
+		if !slices.Equal(delays[0], delays[1]) {
+			t.Fatalf("backoff delays differ between runs: %v and %v", delays[0], delays[1])
+		}
+	})
+}
+
+func TestWithStartJitter(t *testing.T) {
+	const maxJitter = 50 * time.Millisecond
+
+	t.Run("смещения воспроизводимы и в диапазоне", func(t *testing.T) {
+		var offsets [2][]time.Duration
+		for i := range offsets {
+			cfg := newConfig([]Option{WithStartJitter(maxJitter), WithRand(rand.New(rand.NewPCG(5, 6)))})
+			for j := range 10 {
+				offsets[i] = append(offsets[i], cfg.startOffset(fmt.Sprintf("addr%d", j)))
+			}
+		}
+
+		if !slices.Equal(offsets[0], offsets[1]) {
+			t.Fatalf("start offsets differ between runs: %v and %v", offsets[0], offsets[1])
+		}
+
+		for _, d := range offsets[0] {
+			if d < 0 || d >= maxJitter {
+				t.Fatalf("start offset %v out of [0, %v)", d, maxJitter)
+			}
+		}
+	})
+
+	t.Run("добавляется к задержке старта", func(t *testing.T) {
+		cfg := newConfig([]Option{WithStartJitter(maxJitter)})
+		cfg.startDelays = map[string]time.Duration{"addr1": time.Second}
+
+		if d := cfg.startOffset("addr1"); d < time.Second || d >= time.Second+maxJitter {
+			t.Fatalf("startOffset() = %v, want in [%v, %v)", d, time.Second, time.Second+maxJitter)
+		}
+	})
+
+	t.Run("ноль отключает разброс", func(t *testing.T) {
+		cfg := newConfig([]Option{WithStartJitter(0)})
+		if d := cfg.startOffset("addr1"); d != 0 {
+			t.Fatalf("startOffset() = %v, want 0", d)
+		}
+	})
+
+	t.Run("первый успех по-прежнему выигрывает", func(t *testing.T) {
+		getter := NewMockGetter(map[string]map[string]Response{
+			"addr1": {"key1": {Error: errors.New("connection error")}},
+			"addr2": {"key1": {Value: "value2"}},
+		})
+
+		got, err := Get(context.Background(), getter, []string{"addr1", "addr2"}, "key1", WithStartJitter(maxJitter))
+		if err != nil || got != "value2" {
+			t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value2")
+		}
+	})
+}
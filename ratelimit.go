@@ -0,0 +1,19 @@
+package main
+
+import "context"
+
+// Limiter paces calls to an address. *rate.Limiter from
+// golang.org/x/time/rate satisfies it.
+type Limiter interface {
+	Wait(ctx context.Context) error
+}
+
+// WithRateLimiters makes every call to an address wait for its limiter in
+// limiters first. If the wait fails, for example because it would outlast
+// the context deadline, that attempt counts as failed and the other
+// addresses carry on.
+func WithRateLimiters(limiters map[string]Limiter) Option {
+	return func(c *config) {
+		c.limiters = limiters
+	}
+}
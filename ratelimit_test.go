@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// intervalLimiter lets one call through every interval.
+type intervalLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func (l *intervalLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	at := l.next
+	if at.Before(now) {
+		at = now
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && at.After(deadline) {
+		l.mu.Unlock()
+		return errors.New("wait would exceed context deadline")
+	}
+
+	l.next = at.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleep(ctx, realClock{}, at.Sub(now))
+}
+
+func TestGetWithRateLimiters(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("too many requests"), Delay: 5 * time.Millisecond}},
+	})
+	getter := newCountingGetter(mock)
+	limiters := map[string]Limiter{"addr1": &intervalLimiter{interval: 30 * time.Millisecond}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := Get(ctx, getter, []string{"addr1", "addr1", "addr1"}, "key1", WithRateLimiters(limiters))
+	elapsed := time.Since(start)
+
+	if err == nil {
+		t.Fatal("Get() error = nil, want error")
+	}
+
+	if n := getter.TotalCalls(); n != 3 {
+		t.Fatalf("calls = %d, want 3", n)
+	}
+
+	if n := getter.MaxInFlight(); n != 1 {
+		t.Fatalf("max in flight = %d, want calls to the same address serialized", n)
+	}
+
+	if elapsed < 60*time.Millisecond {
+		t.Fatalf("Get() took %v, want at least 60ms for three paced calls", elapsed)
+	}
+}
+
+func TestGetWithRateLimitersPastDeadline(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"limited": {"key1": {Value: "limited"}},
+		"free":    {"key1": {Value: "free", Delay: 20 * time.Millisecond}},
+	})
+	getter := newCountingGetter(mock)
+	limiter := &intervalLimiter{interval: time.Minute, next: time.Now().Add(time.Minute)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	got, err := Get(ctx, getter, []string{"limited", "free"}, "key1", WithRateLimiters(map[string]Limiter{"limited": limiter}))
+	if err != nil || got != "free" {
+		t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "free")
+	}
+
+	if n := getter.Calls("limited"); n != 0 {
+		t.Fatalf("calls to limited = %d, want 0", n)
+	}
+}
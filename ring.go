@@ -0,0 +1,106 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Ring is a consistent hash ring over a set of addresses. Each address is
+// placed on the ring vnodes times, and a key is owned by the first address
+// found walking clockwise from the key's hash. Adding or removing an address
+// therefore only moves the keys it gains or loses.
+type Ring struct {
+	points []ringPoint
+	size   int
+	hash   func(string) uint64
+}
+
+type ringPoint struct {
+	hash    uint64
+	address string
+}
+
+func NewRing(addresses []string, vnodes int) *Ring {
+	return NewRingWithHash(addresses, vnodes, hashKey)
+}
+
+// NewRingWithHash is NewRing placing the addresses and keys on the ring with
+// hash instead of the built-in FNV-1a, e.g. to match how the backends shard
+// their data.
+func NewRingWithHash(addresses []string, vnodes int, hash func(key string) uint64) *Ring {
+	vnodes = max(vnodes, 1)
+	addresses = dedup(addresses)
+
+	r := &Ring{points: make([]ringPoint, 0, len(addresses)*vnodes), size: len(addresses), hash: hash}
+	for _, address := range addresses {
+		for i := range vnodes {
+			r.points = append(r.points, ringPoint{hash: hash(address + "#" + strconv.Itoa(i)), address: address})
+		}
+	}
+
+	slices.SortFunc(r.points, func(a, b ringPoint) int {
+		return cmp.Or(cmp.Compare(a.hash, b.hash), strings.Compare(a.address, b.address))
+	})
+
+	return r
+}
+
+// WithRing orders the addresses of every call by r.Order(key). Addresses
+// missing from the ring are queried after the ones on it, in input order.
+func WithRing(r *Ring) Option {
+	return func(c *config) {
+		c.ring = r
+	}
+}
+
+// Order returns every address on the ring, starting with the owner of key
+// and continuing clockwise.
+func (r *Ring) Order(key string) []string {
+	if len(r.points) == 0 {
+		return nil
+	}
+
+	h := r.hash(key)
+	start, _ := slices.BinarySearchFunc(r.points, h, func(p ringPoint, h uint64) int {
+		return cmp.Compare(p.hash, h)
+	})
+
+	order := make([]string, 0, r.size)
+	seen := make(map[string]struct{}, r.size)
+	for i := 0; i < len(r.points) && len(order) < r.size; i++ {
+		address := r.points[(start+i)%len(r.points)].address
+		if _, ok := seen[address]; !ok {
+			seen[address] = struct{}{}
+			order = append(order, address)
+		}
+	}
+
+	return order
+}
+
+// arrange puts addresses in ring order for key.
+func (r *Ring) arrange(addresses []string, key string) []string {
+	rank := make(map[string]int, r.size)
+	for i, address := range r.Order(key) {
+		rank[address] = i
+	}
+
+	arranged := slices.Clone(addresses)
+	slices.SortStableFunc(arranged, func(a, b string) int {
+		ra, okA := rank[a]
+		rb, okB := rank[b]
+		switch {
+		case okA && okB:
+			return ra - rb
+		case okA:
+			return -1
+		case okB:
+			return 1
+		}
+		return 0
+	})
+
+	return arranged
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+)
+
+func ringAddresses(n int) []string {
+	addresses := make([]string, n)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("addr%d", i+1)
+	}
+
+	return addresses
+}
+
+func TestRingOrder(t *testing.T) {
+	addresses := ringAddresses(5)
+	ring := NewRing(addresses, 50)
+
+	for i := range 100 {
+		key := fmt.Sprintf("key%d", i)
+		order := ring.Order(key)
+
+		if sorted := slices.Sorted(slices.Values(order)); !slices.Equal(sorted, addresses) {
+			t.Fatalf("Order(%q) = %v, want a permutation of %v", key, order, addresses)
+		}
+
+		if again := ring.Order(key); !slices.Equal(again, order) {
+			t.Fatalf("Order(%q) = %v, then %v: want the same order every time", key, order, again)
+		}
+	}
+
+	if order := NewRing(nil, 50).Order("key1"); len(order) != 0 {
+		t.Fatalf("Order() on an empty ring = %v, want empty", order)
+	}
+}
+
+func TestRingRemap(t *testing.T) {
+	const keys = 2000
+	addresses := ringAddresses(10)
+	ring := NewRing(addresses, 100)
+
+	tests := []struct {
+		name      string
+		changed   []string
+		maxMoved  float64
+		onlyOwned string
+	}{
+		{name: "удаление адреса", changed: addresses[:9], maxMoved: 0.2, onlyOwned: addresses[9]},
+		{name: "добавление адреса", changed: append(slices.Clone(addresses), "addr11"), maxMoved: 0.2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changed := NewRing(tt.changed, 100)
+
+			moved := 0
+			for i := range keys {
+				key := fmt.Sprintf("key%d", i)
+				before, after := ring.Order(key)[0], changed.Order(key)[0]
+				if before == after {
+					continue
+				}
+
+				moved++
+				if tt.onlyOwned != "" && before != tt.onlyOwned {
+					t.Fatalf("key %q moved from %s to %s, want only keys of %s to move", key, before, after, tt.onlyOwned)
+				}
+			}
+
+			if fraction := float64(moved) / keys; fraction > tt.maxMoved {
+				t.Fatalf("%.0f%% of keys changed primary, want at most %.0f%%", fraction*100, tt.maxMoved*100)
+			}
+		})
+	}
+}
+
+func TestGetWithRing(t *testing.T) {
+	addresses := ringAddresses(3)
+	ring := NewRing(addresses, 50)
+	order := ring.Order("key1")
+
+	mock := NewMockGetter(map[string]map[string]Response{
+		order[0]: {"key1": {Error: errors.New("connection error")}},
+		order[1]: {"key1": {Value: "value-" + order[1]}},
+		order[2]: {"key1": {Value: "value-" + order[2]}},
+	})
+	getter := newCountingGetter(mock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	got, err := Get(ctx, getter, append(addresses, "offring"), "key1", WithRing(ring), WithMaxConcurrency(1))
+	if err != nil || got != "value-"+order[1] {
+		t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value-"+order[1])
+	}
+
+	for address, want := range map[string]int{order[0]: 1, order[1]: 1, order[2]: 0, "offring": 0} {
+		if n := getter.Calls(address); n != want {
+			t.Fatalf("calls to %s = %d, want %d", address, n, want)
+		}
+	}
+}
+
+func TestNewRingWithHash(t *testing.T) {
+	// A hash that places every point and every key at zero leaves the ring
+	// in address order, with the smallest address owning all keys.
+	zero := func(string) uint64 { return 0 }
+	ring := NewRingWithHash([]string{"addr3", "addr1", "addr2"}, 4, zero)
+
+	for _, key := range []string{"key1", "key2", "key3"} {
+		if order := ring.Order(key); !slices.Equal(order, []string{"addr1", "addr2", "addr3"}) {
+			t.Fatalf("Order(%q) = %v, want [addr1 addr2 addr3]", key, order)
+		}
+	}
+}
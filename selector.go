@@ -0,0 +1,52 @@
+package main
+
+import "errors"
+
+var ErrNoValueSelected = errors.New("selector picked none of the values")
+
+// WithSelector lets selector choose the value Get returns among all the
+// successful responses, passed in the order they arrived, instead of taking
+// the first one. Get then waits for every address to answer, so it is only
+// as fast as the slowest of them; combine it with WithAttemptTimeout to
+// bound that wait. When selector reports false Get fails with
+// ErrNoValueSelected. It applies to the Get variants over strings and is
+// ignored by the typed ones.
+func WithSelector(selector func(candidates []AddressResult) (AddressResult, bool)) Option {
+	return func(c *config) {
+		c.selector = selector
+	}
+}
+
+// WithPartialOnDeadline makes Get settle for the successful responses it
+// already has when the context deadline expires while waiting for more,
+// as WithSelector does: the selector is given those responses and Get
+// returns its pick with a nil error. Get still fails with the context error
+// when nothing succeeded in time, or when the context was cancelled rather
+// than timed out.
+func WithPartialOnDeadline() Option {
+	return func(c *config) {
+		c.partialOnDeadline = true
+	}
+}
+
+// choose returns the candidate picked by selector, or the first one when the
+// values are not strings.
+func choose[T any](selector func([]AddressResult) (AddressResult, bool), candidates []result[T]) (value T, source string, err error) {
+	strs, ok := any(candidates).([]result[string])
+	if !ok {
+		return candidates[0].value, candidates[0].address, nil
+	}
+
+	results := make([]AddressResult, len(strs))
+	for i, c := range strs {
+		results[i] = AddressResult{Address: c.address, Value: c.value, Latency: c.latency}
+	}
+
+	picked, ok := selector(results)
+	if !ok {
+		return value, "", ErrNoValueSelected
+	}
+
+	value, _ = any(picked.Value).(T)
+	return value, picked.Address, nil
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func largestValue(candidates []AddressResult) (AddressResult, bool) {
+	best := candidates[0]
+	for _, c := range candidates[1:] {
+		if c.Value > best.Value {
+			best = c
+		}
+	}
+
+	return best, true
+}
+
+func TestGetWithSelector(t *testing.T) {
+	tests := []struct {
+		name       string
+		responses  map[string]map[string]Response
+		selector   func([]AddressResult) (AddressResult, bool)
+		wantValue  string
+		wantSource string
+		wantErrIs  error
+		wantErr    bool
+	}{
+		{
+			name: "лексикографически наибольшее значение",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "apple"}},
+				"addr2": {"key1": {Value: "pear", Delay: 30 * time.Millisecond}},
+				"addr3": {"key1": {Value: "banana", Delay: 10 * time.Millisecond}},
+			},
+			selector:   largestValue,
+			wantValue:  "pear",
+			wantSource: "addr2",
+		},
+		{
+			name: "упавшие адреса не попадают в кандидаты",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "apple"}},
+				"addr2": {"key1": {Error: errors.New("connection error")}},
+				"addr3": {"key1": {Value: "banana", Delay: 10 * time.Millisecond}},
+			},
+			selector:   largestValue,
+			wantValue:  "banana",
+			wantSource: "addr3",
+		},
+		{
+			name: "селектор ничего не выбрал",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "apple"}},
+				"addr2": {"key1": {Value: "pear"}},
+				"addr3": {"key1": {Value: "banana"}},
+			},
+			selector:  func([]AddressResult) (AddressResult, bool) { return AddressResult{}, false },
+			wantErr:   true,
+			wantErrIs: ErrNoValueSelected,
+		},
+		{
+			name: "все адреса падают — селектор не вызывается",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("error 1")}},
+				"addr2": {"key1": {Error: errors.New("error 2")}},
+				"addr3": {"key1": {Error: errors.New("error 3")}},
+			},
+			selector: func([]AddressResult) (AddressResult, bool) {
+				t.Error("selector called without candidates")
+				return AddressResult{}, false
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, source, err := GetWithSource(ctx, NewMockGetter(tt.responses), []string{"addr1", "addr2", "addr3"}, "key1", WithSelector(tt.selector))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetWithSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("GetWithSource() error = %v, want errors.Is(err, %v) == true", err, tt.wantErrIs)
+			}
+
+			if got != tt.wantValue || source != tt.wantSource {
+				t.Fatalf("GetWithSource() = (%q, %q), want (%q, %q)", got, source, tt.wantValue, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestGetWithPartialOnDeadline(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		opts      []Option
+		wantValue string
+		wantErrIs error
+	}{
+		{
+			name: "лучший из полученных до дедлайна",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "apple", Delay: 10 * time.Millisecond}},
+				"addr2": {"key1": {Value: "pear", Delay: time.Second}},
+			},
+			opts:      []Option{WithSelector(largestValue), WithPartialOnDeadline()},
+			wantValue: "apple",
+		},
+		{
+			name: "без опции — ошибка контекста",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "apple", Delay: 10 * time.Millisecond}},
+				"addr2": {"key1": {Value: "pear", Delay: time.Second}},
+			},
+			opts:      []Option{WithSelector(largestValue)},
+			wantErrIs: context.DeadlineExceeded,
+		},
+		{
+			name: "до дедлайна ничего не получено",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+				"addr2": {"key1": {Value: "pear", Delay: time.Second}},
+			},
+			opts:      []Option{WithSelector(largestValue), WithPartialOnDeadline()},
+			wantErrIs: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			got, err := Get(ctx, NewMockGetter(tt.responses), []string{"addr1", "addr2"}, "key1", tt.opts...)
+
+			if got != tt.wantValue || !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("Get() = (%q, %v), want (%q, %v)", got, err, tt.wantValue, tt.wantErrIs)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import "context"
+
+// Semaphore bounds how many getter calls run at once across every Get that
+// shares it. *semaphore.Weighted from golang.org/x/sync/semaphore satisfies
+// it.
+type Semaphore interface {
+	Acquire(ctx context.Context, n int64) error
+	Release(n int64)
+}
+
+// WithSemaphore makes every getter call hold one unit of sem while it runs.
+// Sharing sem between concurrent Get calls bounds their combined fan-out,
+// where WithMaxConcurrency only bounds a single call. Waiting for sem is
+// aborted when ctx is done, failing that attempt.
+func WithSemaphore(sem Semaphore) Option {
+	return func(c *config) {
+		c.semaphore = sem
+	}
+}
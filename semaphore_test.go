@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// chanSemaphore is a Semaphore of a fixed capacity, handing out one unit at
+// a time.
+type chanSemaphore chan struct{}
+
+func (s chanSemaphore) Acquire(ctx context.Context, n int64) error {
+	for range n {
+		select {
+		case s <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (s chanSemaphore) Release(n int64) {
+	for range n {
+		<-s
+	}
+}
+
+func TestGetWithSemaphore(t *testing.T) {
+	const limit = 2
+
+	responses := map[string]map[string]Response{}
+	addresses := make([]string, 4)
+	for i := range addresses {
+		addresses[i] = fmt.Sprintf("addr%d", i)
+		responses[addresses[i]] = map[string]Response{"key1": {Value: "value", Delay: 10 * time.Millisecond}}
+	}
+	getter := newCountingGetter(NewMockGetter(responses))
+	sem := make(chanSemaphore, limit)
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			if _, err := Get(ctx, getter, addresses, "key1", WithSemaphore(sem)); err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := getter.MaxInFlight(); n > limit {
+		t.Fatalf("max in flight across calls = %d, want at most %d", n, limit)
+	}
+
+	t.Run("ожидание семафора ограничено контекстом", func(t *testing.T) {
+		full := make(chanSemaphore, 1)
+		full <- struct{}{}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := Get(ctx, getter, addresses[:1], "key1", WithSemaphore(full))
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Get() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	})
+}
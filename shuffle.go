@@ -0,0 +1,25 @@
+package main
+
+import (
+	"context"
+	"slices"
+)
+
+// GetShuffled races addresses like Get, but starts them in a random order on
+// every call so that no replica is always hit first.
+func GetShuffled(ctx context.Context, getter Getter, addresses []string, key string, opts ...Option) (string, error) {
+	cfg := newConfig(opts)
+	value, _, err := race[string](ctx, getter, shuffled(addresses, cfg.random()), key, cfg)
+	return value, err
+}
+
+// shuffled returns a random permutation of addresses drawn from r. The input
+// slice is left untouched.
+func shuffled(addresses []string, r *randSource) []string {
+	addresses = slices.Clone(addresses)
+	r.shuffle(len(addresses), func(i, j int) {
+		addresses[i], addresses[j] = addresses[j], addresses[i]
+	})
+
+	return addresses
+}
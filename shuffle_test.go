@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestShuffledPermutation(t *testing.T) {
+	addresses := []string{"addr1", "addr2", "addr3", "addr4", "addr5"}
+
+	want := []string{"addr2", "addr5", "addr3", "addr1", "addr4"}
+
+	got := shuffled(addresses, &randSource{r: rand.New(rand.NewPCG(1, 2))})
+	if !slices.Equal(got, want) {
+		t.Fatalf("shuffled() = %v, want %v", got, want)
+	}
+
+	if !slices.Equal(addresses, []string{"addr1", "addr2", "addr3", "addr4", "addr5"}) {
+		t.Fatalf("shuffled() modified its input: %v", addresses)
+	}
+
+	if again := shuffled(addresses, &randSource{r: rand.New(rand.NewPCG(1, 2))}); !slices.Equal(again, got) {
+		t.Fatalf("shuffled() with the same seed = %v, want %v", again, got)
+	}
+}
+
+func TestGetShuffled(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		addresses []string
+		wantValue string
+		wantErr   bool
+	}{
+		{
+			name: "первый адрес падает, второй успешен",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			wantValue: "value2",
+		},
+		{
+			name: "все адреса падают",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("error 1")}},
+				"addr2": {"key1": {Error: errors.New("error 2")}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			wantErr:   true,
+		},
+		{
+			name:      "пустой список адресов",
+			responses: map[string]map[string]Response{},
+			addresses: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetShuffled(ctx, NewMockGetter(tt.responses), tt.addresses, "key1", WithRand(rand.New(rand.NewPCG(1, 2))))
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetShuffled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetShuffled() = %q, want %q", got, tt.wantValue)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// FlightGroup coalesces concurrent Get calls for the same key and address set
+// into one operation whose result every caller receives. The shared operation
+// runs with the context of the caller that started it; the other callers only
+// stop waiting when their own context is done. The zero value is ready to use.
+type FlightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+type flight struct {
+	done   chan struct{}
+	value  any
+	source string
+	err    error
+}
+
+// WithSingleFlight shares in-flight Get operations through g.
+func WithSingleFlight(g *FlightGroup) Option {
+	return func(c *config) {
+		c.flights = g
+	}
+}
+
+func (g *FlightGroup) do(ctx context.Context, key string, fn func() (any, string, error)) (any, string, error) {
+	g.mu.Lock()
+	if f, ok := g.flights[key]; ok {
+		g.mu.Unlock()
+
+		select {
+		case <-f.done:
+			return f.value, f.source, f.err
+		case <-ctx.Done():
+			return nil, "", canceled(ctx)
+		}
+	}
+
+	if g.flights == nil {
+		g.flights = make(map[string]*flight)
+	}
+
+	f := &flight{done: make(chan struct{})}
+	g.flights[key] = f
+	g.mu.Unlock()
+
+	f.value, f.source, f.err = fn()
+
+	g.mu.Lock()
+	delete(g.flights, key)
+	g.mu.Unlock()
+	close(f.done)
+
+	return f.value, f.source, f.err
+}
+
+// flightKey identifies an operation by the type of its values, its key and
+// the set of addresses, regardless of their order or repetitions. The type
+// keeps, say, GetBytes from joining a Get over the same key and addresses.
+func flightKey[T any](key string, addresses []string) string {
+	set := slices.Clone(addresses)
+	slices.Sort(set)
+	set = slices.Compact(set)
+
+	var zero T
+	return fmt.Sprintf("%T", zero) + "\x00" + key + "\x00" + strings.Join(set, "\x00")
+}
+
+// WithInFlightDedup coalesces attempts that run at the same time against the
+// same address into one getter call whose result each of them receives.
+// Unlike WithDedup it keeps repeated addresses in the list, so they still
+// count as separate attempts, but never hits an address twice at once. A
+// later attempt, such as a retry after the shared call failed, calls the
+// getter again.
+func WithInFlightDedup() Option {
+	return func(c *config) {
+		c.inFlightDedup = true
+	}
+}
+
+// sharedGet calls getter through the attempts of cfg, if any, so that
+// concurrent attempts against one address make a single call.
+func sharedGet[T any](ctx context.Context, getter TypedGetter[T], address, key string, cfg config) (T, error) {
+	get := func() (T, error) {
+		if cfg.calls != nil {
+			cfg.calls.Add(1)
+		}
+
+		return safeGet(ctx, getter, address, key)
+	}
+
+	if cfg.attempts == nil {
+		return get()
+	}
+
+	shared, _, err := cfg.attempts.do(ctx, address, func() (any, string, error) {
+		value, err := get()
+		return value, address, err
+	})
+	value, _ := shared.(T)
+
+	return value, err
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetWithSingleFlight(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection error"), Delay: 50 * time.Millisecond}},
+		"addr2": {"key1": {Value: "value2", Delay: 100 * time.Millisecond}},
+	})
+	getter := newCountingGetter(mock)
+	group := &FlightGroup{}
+
+	var wg sync.WaitGroup
+	for i := range 100 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			addresses := []string{"addr1", "addr2"}
+			if i%2 == 1 {
+				addresses = []string{"addr2", "addr1"}
+			}
+
+			got, err := Get(context.Background(), getter, addresses, "key1", WithSingleFlight(group))
+			if err != nil || got != "value2" {
+				t.Errorf("Get() = (%q, %v), want (%q, nil)", got, err, "value2")
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	for _, address := range []string{"addr1", "addr2"} {
+		if n := getter.Calls(address); n != 1 {
+			t.Fatalf("calls to %s = %d, want 1", address, n)
+		}
+	}
+}
+
+func TestGetWithSingleFlightAcrossTypes(t *testing.T) {
+	strs := NewMockGetter(map[string]map[string]Response{"addr1": {"key1": {Value: "text", Delay: 50 * time.Millisecond}}})
+	raw := &MockTypedGetter[[]byte]{Responses: map[string]map[string]TypedResponse[[]byte]{
+		"addr1": {"key1": {Value: []byte("bytes"), Delay: 20 * time.Millisecond}},
+	}}
+	group := &FlightGroup{}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		if got, err := Get(context.Background(), strs, []string{"addr1"}, "key1", WithSingleFlight(group)); err != nil || got != "text" {
+			t.Errorf("Get() = (%q, %v), want (%q, nil)", got, err, "text")
+		}
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	got, err := GetBytes(context.Background(), raw, []string{"addr1"}, "key1", WithSingleFlight(group))
+	if err != nil || string(got) != "bytes" {
+		t.Fatalf("GetBytes() = (%q, %v), want (%q, nil)", got, err, "bytes")
+	}
+
+	<-done
+}
+
+func TestFlightKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  []string
+		keyA  string
+		keyB  string
+		equal bool
+	}{
+		{name: "порядок адресов не важен", a: []string{"addr1", "addr2"}, b: []string{"addr2", "addr1"}, keyA: "k", keyB: "k", equal: true},
+		{name: "повторы не важны", a: []string{"addr1", "addr1"}, b: []string{"addr1"}, keyA: "k", keyB: "k", equal: true},
+		{name: "разные ключи", a: []string{"addr1"}, b: []string{"addr1"}, keyA: "k1", keyB: "k2"},
+		{name: "разные наборы адресов", a: []string{"addr1"}, b: []string{"addr1", "addr2"}, keyA: "k", keyB: "k"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := flightKey[string](tt.keyA, tt.a) == flightKey[string](tt.keyB, tt.b); got != tt.equal {
+				t.Fatalf("flightKey(%q, %v) == flightKey(%q, %v) is %v, want %v", tt.keyA, tt.a, tt.keyB, tt.b, got, tt.equal)
+			}
+		})
+	}
+}
+
+func TestGetWithInFlightDedup(t *testing.T) {
+	errConn := errors.New("connection error")
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		opts      []Option
+		want      string
+		wantErrs  int
+		wantCalls int
+	}{
+		{
+			name: "повтор адреса разделяет один вызов",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errConn, Delay: 50 * time.Millisecond}},
+			},
+			opts:      []Option{WithInFlightDedup()},
+			wantErrs:  2,
+			wantCalls: 1,
+		},
+		{
+			name: "успех доходит до всех попыток",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: 50 * time.Millisecond}},
+			},
+			opts:      []Option{WithInFlightDedup()},
+			want:      "value1",
+			wantCalls: 1,
+		},
+		{
+			name: "без опции каждый повтор вызывает getter",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errConn, Delay: 50 * time.Millisecond}},
+			},
+			wantErrs:  2,
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(tt.responses))
+
+			got, err := Get(context.Background(), getter, []string{"addr1", "addr1"}, "key1", tt.opts...)
+			if got != tt.want {
+				t.Fatalf("Get() = %q, want %q", got, tt.want)
+			}
+
+			if tt.wantErrs == 0 {
+				if err != nil {
+					t.Fatalf("Get() error = %v, want nil", err)
+				}
+			} else {
+				var multi *MultiError
+				if !errors.As(err, &multi) || len(multi.Errors) != tt.wantErrs {
+					t.Fatalf("Get() error = %v, want MultiError of %d", err, tt.wantErrs)
+				}
+
+				for _, e := range multi.Errors {
+					if !errors.Is(e.Err, errConn) {
+						t.Fatalf("attempt error = %v, want %v", e, errConn)
+					}
+				}
+			}
+
+			if n := getter.Calls("addr1"); n != tt.wantCalls {
+				t.Fatalf("calls to addr1 = %d, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+type Outcome int
+
+const (
+	OutcomeSuccess Outcome = iota
+	OutcomeFailure
+	OutcomeCancelled
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case OutcomeSuccess:
+		return "success"
+	case OutcomeFailure:
+		return "failure"
+	case OutcomeCancelled:
+		return "cancelled"
+	}
+
+	return "unknown"
+}
+
+// AttemptStat describes one queried address. Start is the offset from the
+// beginning of the operation at which the address was started.
+type AttemptStat struct {
+	Address  string
+	Start    time.Duration
+	Duration time.Duration
+	Outcome  Outcome
+}
+
+// GetWithStats works like Get but also describes every address it started,
+// in start order. Attempts still running when Get returns, because another
+// address won or ctx was done, are reported as cancelled at that moment.
+func GetWithStats(ctx context.Context, getter Getter, addresses []string, key string, opts ...Option) (value string, stats []AttemptStat, err error) {
+	cfg := newConfig(opts)
+	cfg.report = &report{}
+
+	value, _, err = race[string](ctx, getter, addresses, key, cfg)
+	return value, cfg.report.stats(), err
+}
+
+// GetWithAttempts works like Get but also returns how many getter calls,
+// failed ones and retries included, were made before it returned.
+func GetWithAttempts(ctx context.Context, getter Getter, addresses []string, key string, opts ...Option) (value string, attempts int, err error) {
+	cfg := newConfig(opts)
+	cfg.calls = new(atomic.Int64)
+
+	value, _, err = race[string](ctx, getter, addresses, key, cfg)
+	return value, int(cfg.calls.Load()), err
+}
+
+// GetVerbose works like Get but also returns the failures observed before
+// it returned, in the order they arrived, each an *AddressError naming its
+// address. On success they tell which replicas are degrading even though
+// the call as a whole went through.
+func GetVerbose(ctx context.Context, getter Getter, addresses []string, key string, opts ...Option) (value string, priorErrors []error, err error) {
+	cfg := newConfig(opts)
+	cfg.report = &report{}
+
+	value, _, err = race[string](ctx, getter, addresses, key, cfg)
+	return value, cfg.report.failures(), err
+}
+
+// GetWithCompletionOrder works like Get but also returns the addresses whose
+// attempts finished, successfully or not, before it returned, in the order
+// they finished. Addresses still running at that moment are left out, so
+// consistently slow replicas show up late or not at all.
+func GetWithCompletionOrder(ctx context.Context, getter Getter, addresses []string, key string, opts ...Option) (value string, order []string, err error) {
+	cfg := newConfig(opts)
+	cfg.report = &report{}
+
+	value, _, err = race[string](ctx, getter, addresses, key, cfg)
+	return value, cfg.report.completed(), err
+}
+
+// report records what happened to each address during one run, for the Get
+// variants that expose more than the winning value. Only the goroutine
+// running the race touches it.
+type report struct {
+	clock    Clock
+	started  time.Time
+	attempts []attemptReport
+	order    []int
+}
+
+type attemptReport struct {
+	address    string
+	start, end time.Time
+	launched   bool
+	done       bool
+	outcome    Outcome
+	err        error
+}
+
+func (r *report) begin(n int, clock Clock) {
+	r.clock = clock
+	r.started = clock.Now()
+	r.attempts = make([]attemptReport, n)
+}
+
+func (r *report) launched(i int, address string) {
+	r.attempts[i] = attemptReport{address: address, start: r.clock.Now(), launched: true}
+}
+
+// finished records the result of attempt i. cancelled tells whether the
+// operation had already ended, in which case an error is a cancellation.
+func (r *report) finished(i int, err error, cancelled bool) {
+	a := &r.attempts[i]
+	a.end, a.done, a.err = r.clock.Now(), true, err
+	switch {
+	case err == nil:
+		a.outcome = OutcomeSuccess
+	case cancelled:
+		a.outcome = OutcomeCancelled
+	default:
+		a.outcome = OutcomeFailure
+	}
+
+	r.order = append(r.order, i)
+}
+
+// end marks the attempts still running as cancelled.
+func (r *report) end() {
+	now := r.clock.Now()
+	for i := range r.attempts {
+		if a := &r.attempts[i]; a.launched && !a.done {
+			a.end, a.done, a.outcome = now, true, OutcomeCancelled
+		}
+	}
+}
+
+func (r *report) stats() []AttemptStat {
+	var stats []AttemptStat
+	for _, a := range r.attempts {
+		if a.launched {
+			stats = append(stats, AttemptStat{
+				Address:  a.address,
+				Start:    a.start.Sub(r.started),
+				Duration: a.end.Sub(a.start),
+				Outcome:  a.outcome,
+			})
+		}
+	}
+
+	return stats
+}
+
+// failures returns the errors of the failed attempts in completion order.
+func (r *report) failures() []error {
+	var errs []error
+	for _, i := range r.order {
+		if a := r.attempts[i]; a.outcome == OutcomeFailure {
+			errs = append(errs, &AddressError{Address: a.address, Err: a.err})
+		}
+	}
+
+	return errs
+}
+
+// completed returns the addresses of the finished attempts in completion
+// order.
+func (r *report) completed() []string {
+	order := make([]string, len(r.order))
+	for j, i := range r.order {
+		order[j] = r.attempts[i].address
+	}
+
+	return order
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetWithStats(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		addresses []string
+		ttl       time.Duration
+		opts      []Option
+		wantErr   bool
+		want      []AttemptStat
+	}{
+		{
+			name: "успех: победитель, ошибка и отменённая попытка",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error"), Delay: 10 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2", Delay: 40 * time.Millisecond}},
+				"addr3": {"key1": {Value: "value3", Delay: time.Second}},
+			},
+			addresses: []string{"addr1", "addr2", "addr3"},
+			ttl:       time.Second,
+			want: []AttemptStat{
+				{Address: "addr1", Duration: 10 * time.Millisecond, Outcome: OutcomeFailure},
+				{Address: "addr2", Duration: 40 * time.Millisecond, Outcome: OutcomeSuccess},
+				{Address: "addr3", Duration: 40 * time.Millisecond, Outcome: OutcomeCancelled},
+			},
+		},
+		{
+			name: "все адреса падают",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("error 1")}},
+				"addr2": {"key1": {Error: errors.New("error 2"), Delay: 20 * time.Millisecond}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			ttl:       time.Second,
+			wantErr:   true,
+			want: []AttemptStat{
+				{Address: "addr1", Outcome: OutcomeFailure},
+				{Address: "addr2", Duration: 20 * time.Millisecond, Outcome: OutcomeFailure},
+			},
+		},
+		{
+			name: "отмена контекста",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: time.Second}},
+				"addr2": {"key1": {Value: "value2", Delay: 30 * time.Millisecond}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			ttl:       60 * time.Millisecond,
+			opts:      []Option{WithHedgeDelay(20 * time.Millisecond), WithMaxConcurrency(1)},
+			wantErr:   true,
+			want: []AttemptStat{
+				{Address: "addr1", Duration: 60 * time.Millisecond, Outcome: OutcomeCancelled},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ttl)
+			defer cancel()
+
+			_, stats, err := GetWithStats(ctx, NewMockGetter(tt.responses), tt.addresses, "key1", tt.opts...)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetWithStats() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(stats) != len(tt.want) {
+				t.Fatalf("GetWithStats() stats = %+v, want %d entries", stats, len(tt.want))
+			}
+
+			for i, want := range tt.want {
+				got := stats[i]
+				if got.Address != want.Address || got.Outcome != want.Outcome {
+					t.Fatalf("stats[%d] = %s %v, want %s %v", i, got.Address, got.Outcome, want.Address, want.Outcome)
+				}
+
+				if got.Duration < want.Duration || got.Duration > want.Duration+100*time.Millisecond {
+					t.Fatalf("stats[%d].Duration = %v, want about %v", i, got.Duration, want.Duration)
+				}
+
+				if got.Start < 0 || got.Start > 10*time.Millisecond {
+					t.Fatalf("stats[%d].Start = %v, want close to zero", i, got.Start)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithAttempts(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     map[string]int
+		addresses    []string
+		opts         []Option
+		wantValue    string
+		wantAttempts int
+		wantErr      bool
+	}{
+		{
+			name:         "победитель третьим по счёту",
+			failures:     map[string]int{"addr1": -1, "addr2": -1},
+			addresses:    []string{"addr1", "addr2", "addr3", "addr4"},
+			opts:         []Option{WithMaxConcurrency(1)},
+			wantValue:    "value-addr3",
+			wantAttempts: 3,
+		},
+		{
+			name:         "повторы тоже считаются",
+			failures:     map[string]int{"addr1": -1, "addr2": 1},
+			addresses:    []string{"addr1", "addr2"},
+			opts:         []Option{WithMaxConcurrency(1), WithRetry(3)},
+			wantValue:    "value-addr2",
+			wantAttempts: 5,
+		},
+		{
+			name:         "все адреса падают",
+			failures:     map[string]int{"addr1": -1, "addr2": -1},
+			addresses:    []string{"addr1", "addr2"},
+			wantAttempts: 2,
+			wantErr:      true,
+		},
+		{
+			name:         "пустой список адресов",
+			wantAttempts: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, attempts, err := GetWithAttempts(ctx, newFlakyGetter(tt.failures, 0), tt.addresses, "key1", tt.opts...)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetWithAttempts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.wantValue || attempts != tt.wantAttempts {
+				t.Fatalf("GetWithAttempts() = (%q, %d), want (%q, %d)", got, attempts, tt.wantValue, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestGetVerbose(t *testing.T) {
+	errConn := errors.New("connection error")
+	errTimeout := errors.New("timeout")
+
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		wantValue string
+		wantPrior []string
+	}{
+		{
+			name: "первый падает, второй успешен",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errConn}},
+				"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+				"addr3": {"key1": {Value: "value3", Delay: time.Second}},
+			},
+			wantValue: "value2",
+			wantPrior: []string{"addr1: connection error"},
+		},
+		{
+			name: "ошибки в порядке поступления",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errTimeout, Delay: 10 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2", Delay: 30 * time.Millisecond}},
+				"addr3": {"key1": {Error: errConn}},
+			},
+			wantValue: "value2",
+			wantPrior: []string{"addr3: connection error", "addr1: timeout"},
+		},
+		{
+			name: "успех без ошибок",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1"}},
+				"addr2": {"key1": {Value: "value2", Delay: time.Second}},
+				"addr3": {"key1": {Value: "value3", Delay: time.Second}},
+			},
+			wantValue: "value1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			got, prior, err := GetVerbose(ctx, NewMockGetter(tt.responses), []string{"addr1", "addr2", "addr3"}, "key1")
+			if err != nil || got != tt.wantValue {
+				t.Fatalf("GetVerbose() = (%q, %v), want (%q, nil)", got, err, tt.wantValue)
+			}
+
+			if len(prior) != len(tt.wantPrior) {
+				t.Fatalf("GetVerbose() prior errors = %v, want %v", prior, tt.wantPrior)
+			}
+
+			for i, want := range tt.wantPrior {
+				if prior[i].Error() != want {
+					t.Fatalf("GetVerbose() prior errors = %v, want %v", prior, tt.wantPrior)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithCompletionOrder(t *testing.T) {
+	errConn := errors.New("connection error")
+
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		addresses []string
+		wantValue string
+		wantErr   bool
+		wantOrder []string
+	}{
+		{
+			name: "быстрый раньше медленного",
+			responses: map[string]map[string]Response{
+				"slow": {"key1": {Error: errConn, Delay: 40 * time.Millisecond}},
+				"fast": {"key1": {Error: errConn, Delay: 10 * time.Millisecond}},
+			},
+			addresses: []string{"slow", "fast"},
+			wantErr:   true,
+			wantOrder: []string{"fast", "slow"},
+		},
+		{
+			name: "незавершённые не попадают",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errConn}},
+				"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+				"addr3": {"key1": {Value: "value3", Delay: time.Second}},
+			},
+			addresses: []string{"addr3", "addr2", "addr1"},
+			wantValue: "value2",
+			wantOrder: []string{"addr1", "addr2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			got, order, err := GetWithCompletionOrder(ctx, NewMockGetter(tt.responses), tt.addresses, "key1")
+			if got != tt.wantValue || (err != nil) != tt.wantErr {
+				t.Fatalf("GetWithCompletionOrder() = (%q, %v), want %q and error %v", got, err, tt.wantValue, tt.wantErr)
+			}
+
+			if !slices.Equal(order, tt.wantOrder) {
+				t.Fatalf("GetWithCompletionOrder() order = %v, want %v", order, tt.wantOrder)
+			}
+		})
+	}
+}
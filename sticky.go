@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"hash/fnv"
+	"slices"
+)
+
+// GetSticky queries the addresses one at a time, starting with a primary
+// picked by hashing key, so the same key keeps landing on the same replica
+// and its caches stay warm. If the primary fails, the other addresses follow
+// in a fixed order. Both depend only on the set of addresses, not on their
+// order in the slice. opts can relax the one-at-a-time default, e.g. with
+// WithHedgeDelay.
+func GetSticky(ctx context.Context, getter Getter, addresses []string, key string, opts ...Option) (string, error) {
+	cfg := newConfig(append([]Option{WithMaxConcurrency(1)}, opts...))
+	value, _, err := race[string](ctx, getter, stickyOrder(addresses, key, cfg.hasher()), key, cfg)
+	return value, err
+}
+
+// WithHashFunc makes GetSticky pick the primary of a key with hash instead of
+// the built-in FNV-1a, so that it lands on the replica the backends shard
+// the key to. Consistent hash rings take their hash function when they are
+// built, see NewRingWithHash.
+func WithHashFunc(hash func(key string) uint64) Option {
+	return func(c *config) {
+		c.hash = hash
+	}
+}
+
+// hasher returns the configured hash function, or the built-in one.
+func (c config) hasher() func(string) uint64 {
+	if c.hash != nil {
+		return c.hash
+	}
+
+	return hashKey
+}
+
+// stickyOrder sorts addresses and rotates them so the primary for key, as
+// picked by hash, comes first.
+func stickyOrder(addresses []string, key string, hash func(string) uint64) []string {
+	sorted := slices.Clone(addresses)
+	slices.Sort(sorted)
+	if len(sorted) == 0 {
+		return sorted
+	}
+
+	primary := int(hash(key) % uint64(len(sorted)))
+	return append(sorted[primary:], sorted[:primary]...)
+}
+
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	return h.Sum64()
+}
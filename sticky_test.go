@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestStickyOrder(t *testing.T) {
+	addresses := []string{"addr1", "addr2", "addr3", "addr4"}
+	reversed := slices.Clone(addresses)
+	slices.Reverse(reversed)
+
+	for _, key := range []string{"key1", "key2", "key3", "user:42"} {
+		order := stickyOrder(addresses, key, hashKey)
+
+		if again := stickyOrder(addresses, key, hashKey); !slices.Equal(again, order) {
+			t.Fatalf("stickyOrder(%q) = %v, then %v: want the same order every time", key, order, again)
+		}
+
+		if other := stickyOrder(reversed, key, hashKey); !slices.Equal(other, order) {
+			t.Fatalf("stickyOrder(%q) = %v for reversed input, want %v", key, other, order)
+		}
+
+		if sorted := slices.Sorted(slices.Values(order)); !slices.Equal(sorted, addresses) {
+			t.Fatalf("stickyOrder(%q) = %v, want a permutation of %v", key, order, addresses)
+		}
+
+		if want := addresses[hashKey(key)%uint64(len(addresses))]; order[0] != want {
+			t.Fatalf("stickyOrder(%q) starts with %s, want %s", key, order[0], want)
+		}
+	}
+}
+
+func TestGetSticky(t *testing.T) {
+	addresses := []string{"addr1", "addr2", "addr3"}
+	order := stickyOrder(addresses, "key1", hashKey)
+
+	tests := []struct {
+		name      string
+		failing   []string
+		wantValue string
+		wantCalls map[string]int
+	}{
+		{
+			name:      "отвечает основной адрес",
+			wantValue: "value-" + order[0],
+			wantCalls: map[string]int{order[0]: 1, order[1]: 0, order[2]: 0},
+		},
+		{
+			name:      "основной адрес падает — следующий по порядку",
+			failing:   []string{order[0]},
+			wantValue: "value-" + order[1],
+			wantCalls: map[string]int{order[0]: 1, order[1]: 1, order[2]: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := map[string]map[string]Response{}
+			for _, address := range addresses {
+				responses[address] = map[string]Response{"key1": {Value: "value-" + address}}
+			}
+
+			for _, address := range tt.failing {
+				responses[address] = map[string]Response{"key1": {Error: errors.New("connection error")}}
+			}
+
+			getter := newCountingGetter(NewMockGetter(responses))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetSticky(ctx, getter, addresses, "key1")
+			if err != nil || got != tt.wantValue {
+				t.Fatalf("GetSticky() = (%q, %v), want (%q, nil)", got, err, tt.wantValue)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetStickyWithHashFunc(t *testing.T) {
+	addresses := []string{"addr1", "addr2", "addr3"}
+	responses := map[string]map[string]Response{}
+	for _, address := range addresses {
+		responses[address] = map[string]Response{"key1": {Value: "value-" + address}}
+	}
+
+	// The sorted addresses are addr1, addr2, addr3, so a hash of n picks
+	// addresses[n%3] as the primary.
+	defaultPrimary := stickyOrder(addresses, "key1", hashKey)[0]
+	shifted := uint64(slices.Index(addresses, defaultPrimary) + 1)
+	custom := func(string) uint64 { return shifted }
+	wantPrimary := addresses[shifted%3]
+
+	getter := newCountingGetter(NewMockGetter(responses))
+	got, err := GetSticky(context.Background(), getter, addresses, "key1", WithHashFunc(custom))
+	if err != nil || got != "value-"+wantPrimary {
+		t.Fatalf("GetSticky() = (%q, %v), want (%q, nil)", got, err, "value-"+wantPrimary)
+	}
+
+	if n := getter.Calls(defaultPrimary); n != 0 {
+		t.Fatalf("calls to the default primary %s = %d, want 0", defaultPrimary, n)
+	}
+}
@@ -2,12 +2,791 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+var (
+	ErrNilGetter    = errors.New("getter is nil")
+	ErrEmptyKey     = errors.New("key is empty")
+	ErrTimeout      = errors.New("operation timed out")
+	ErrStopped      = errors.New("stop channel closed")
+	ErrSoftDeadline = errors.New("soft deadline passed before the address was queried")
+	ErrEmptyValue   = errors.New("address returned an empty value")
+
+	// ErrNoAcceptableValue fails every address whose value was turned down
+	// by the accept func of WithAcceptFunc, so a Get whose successful
+	// responses were all rejected fails with a MultiError matching it.
+	ErrNoAcceptableValue = errors.New("address returned no acceptable value")
+
+	// ErrWinnerFound is the cause, as reported by context.Cause, of the
+	// cancellation of the attempts still running when another address won.
+	ErrWinnerFound = errors.New("another address won")
+
+	// ErrKeyNotFound is what getters should report, wrapped or not, for a
+	// key an address does not hold. When every address reports it Get
+	// returns ErrKeyNotFound itself instead of a MultiError, so a missing
+	// key is not mistaken for an outage; as soon as one address failed any
+	// other way, the usual MultiError is returned.
+	ErrKeyNotFound = errors.New("key not found")
 )
 
 type Getter interface {
 	Get(ctx context.Context, address, key string) (string, error)
 }
 
-func Get(ctx context.Context, getter Getter, addresses []string, key string) (string, error) {
-	return "", nil
+// Get calls getter for every address concurrently and returns the first
+// successful value. opts tune how the addresses are queried; without any,
+// all of them are queried at once, each exactly one time.
+func Get(ctx context.Context, getter Getter, addresses []string, key string, opts ...Option) (string, error) {
+	value, _, err := GetWithSource(ctx, getter, addresses, key, opts...)
+	return value, err
+}
+
+// GetWithSource works like Get but also reports the address whose response
+// was used. source is empty whenever no address succeeded.
+func GetWithSource(ctx context.Context, getter Getter, addresses []string, key string, opts ...Option) (value, source string, err error) {
+	// The addresses are the single tier that endpoints without metadata
+	// make up, raced on the path of GetEndpoints.
+	tiers := [1]tier{{addresses: addresses}}
+	return raceTiers(ctx, getter, tiers[:], key, newConfig(opts))
+}
+
+// GetWithLatency works like Get but also reports how long the operation took:
+// until the winning response arrived, or until the last attempt failed. It is
+// measured on the monotonic clock.
+func GetWithLatency(ctx context.Context, getter Getter, addresses []string, key string, opts ...Option) (value string, latency time.Duration, err error) {
+	cfg := newConfig(opts)
+	start := cfg.timeSource().Now()
+	value, _, err = race[string](ctx, getter, addresses, key, cfg)
+	return value, cfg.timeSource().Now().Sub(start), err
+}
+
+// GetWithTimeout works like Get but gives up after timeout even when ctx
+// would allow it to go on. Running out of that time returns an error
+// matching ErrTimeout; a ctx that is done first wins and its own error is
+// returned, so the two can be told apart.
+func GetWithTimeout(ctx context.Context, getter Getter, addresses []string, key string, timeout time.Duration, opts ...Option) (string, error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, timeout, fmt.Errorf("%w after %v", ErrTimeout, timeout))
+	defer cancel()
+
+	return Get(ctx, getter, addresses, key, opts...)
+}
+
+// GetWithStop works like Get but also gives up as soon as stop is closed,
+// for callers that signal cancellation with a channel instead of a context.
+// A closed stop is reported like a cancelled ctx: the error matches both
+// context.Canceled and ErrStopped.
+func GetWithStop(ctx context.Context, getter Getter, addresses []string, key string, stop <-chan struct{}, opts ...Option) (string, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	go func() {
+		select {
+		case <-stop:
+			cancel(ErrStopped)
+		case <-ctx.Done():
+		}
+	}()
+
+	return Get(ctx, getter, addresses, key, opts...)
+}
+
+// GetWithSoftDeadline works like Get but starts no new address once soft
+// has passed, leaving the attempts already running up to grace more to
+// finish before they are cancelled. Addresses that were never started fail
+// with ErrSoftDeadline.
+func GetWithSoftDeadline(ctx context.Context, getter Getter, addresses []string, key string, soft, grace time.Duration, opts ...Option) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, soft+grace)
+	defer cancel()
+
+	cfg := newConfig(opts)
+	cfg.softDeadline = soft
+
+	value, _, err := race[string](ctx, getter, addresses, key, cfg)
+	return value, err
+}
+
+// GetSimple is Get for callers without a context, e.g. scripts: it runs
+// under a fresh context that expires after timeout, or never when timeout
+// is zero.
+func GetSimple(getter Getter, addresses []string, key string, timeout time.Duration, opts ...Option) (string, error) {
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return Get(ctx, getter, addresses, key, opts...)
+}
+
+// GetLimited is Get with WithMaxConcurrency(maxConcurrency).
+func GetLimited(ctx context.Context, getter Getter, addresses []string, key string, maxConcurrency int) (string, error) {
+	return Get(ctx, getter, addresses, key, WithMaxConcurrency(maxConcurrency))
+}
+
+// GetHedged is Get with WithHedgeDelay(hedgeDelay).
+func GetHedged(ctx context.Context, getter Getter, addresses []string, key string, hedgeDelay time.Duration) (string, error) {
+	return Get(ctx, getter, addresses, key, WithHedgeDelay(hedgeDelay))
+}
+
+// GetPrimaryThenFanout gives addresses[0] a head start of primaryGrace: only
+// if it has not succeeded by then, or fails earlier, are all the other
+// addresses queried at once. The first success of any address wins.
+func GetPrimaryThenFanout(ctx context.Context, getter Getter, addresses []string, key string, primaryGrace time.Duration, opts ...Option) (string, error) {
+	cfg := newConfig(opts)
+	cfg.hedgeDelay, cfg.hedgeAll = primaryGrace, true
+
+	value, _, err := race[string](ctx, getter, addresses, key, cfg)
+	return value, err
+}
+
+// GetWithAttemptTimeout is Get with WithAttemptTimeout(perAttempt).
+func GetWithAttemptTimeout(ctx context.Context, getter Getter, addresses []string, key string, perAttempt time.Duration) (string, error) {
+	return Get(ctx, getter, addresses, key, WithAttemptTimeout(perAttempt))
+}
+
+// GetWithRetry is Get with WithRetry(maxAttempts).
+func GetWithRetry(ctx context.Context, getter Getter, addresses []string, key string, maxAttempts int) (string, error) {
+	return Get(ctx, getter, addresses, key, WithRetry(maxAttempts))
+}
+
+// GetWithBackoff is Get with WithRetry(maxAttempts) and
+// WithBackoff(base, maxBackoff), followed by opts.
+func GetWithBackoff(ctx context.Context, getter Getter, addresses []string, key string, base, maxBackoff time.Duration, maxAttempts int, opts ...Option) (string, error) {
+	opts = append([]Option{WithRetry(maxAttempts), WithBackoff(base, maxBackoff)}, opts...)
+	return Get(ctx, getter, addresses, key, opts...)
+}
+
+// race queries addresses concurrently, as allowed by cfg, and returns the
+// first successful value together with the address that produced it.
+func race[T any](ctx context.Context, getter TypedGetter[T], addresses []string, key string, cfg config) (value T, source string, err error) {
+	var zero T
+	if err := validate(getter, key); err != nil {
+		return zero, "", err
+	}
+
+	// Selectors pick among strings, so the typed variants ignore them and
+	// wait for the first success only.
+	if _, ok := any(zero).(string); !ok {
+		cfg.selector, cfg.partialOnDeadline = nil, false
+	}
+
+	// A context that cannot see an attempt through is turned down before
+	// the resolver, health checks and hooks get to run.
+	cfg = cfg.withContext(ctx)
+	if err := checkDeadline(ctx, cfg.deadlineSlack); err != nil {
+		return zero, "", err
+	}
+
+	addresses, err = prepare(addresses, key, cfg)
+	if err != nil || len(addresses) == 0 {
+		return zero, "", err
+	}
+
+	if cfg.flights != nil {
+		shared, source, err := cfg.flights.do(ctx, flightKey[T](key, addresses), func() (any, string, error) {
+			return run(ctx, getter, addresses, key, cfg)
+		})
+		value, _ = shared.(T)
+		return value, source, err
+	}
+
+	return run(ctx, getter, addresses, key, cfg)
+}
+
+// run is the engine behind race, working on already prepared addresses.
+func run[T any](ctx context.Context, getter TypedGetter[T], addresses []string, key string, cfg config) (value T, source string, err error) {
+	var zero T
+	if cfg.tracer != nil {
+		var span Span
+		ctx, span = cfg.tracer.Start(ctx, "Get", slog.String("key", key))
+		defer func() { endOperation(span, source, err) }()
+	}
+
+	// Cancelling on return tells the losing attempts to stop, and the
+	// buffered channel lets them report without anyone left to receive.
+	// The cause tells them whether they lost to a winner.
+	ctx, cancel := context.WithCancelCause(ctx)
+	var watchLeaks func()
+	defer func() {
+		if err == nil {
+			cancel(ErrWinnerFound)
+		} else {
+			cancel(nil)
+		}
+
+		if watchLeaks != nil {
+			watchLeaks()
+		}
+	}()
+
+	if cfg.report != nil {
+		cfg.report.begin(len(addresses), cfg.timeSource())
+		defer cfg.report.end()
+	}
+
+	results := make(chan result[T], len(addresses))
+	next, inFlight := 0, 0
+	limit := cfg.concurrencyLimit()
+	if cfg.inFlightDedup {
+		cfg.attempts = &FlightGroup{}
+	}
+
+	var answered []bool
+	if cfg.leakHook != nil {
+		answered = make([]bool, len(addresses))
+		watchLeaks = func() {
+			if inFlight > 0 {
+				go awaitLeaks(results, answered[:next], inFlight, addresses, cfg.timeSource(), cfg.leakHook)
+			}
+		}
+	}
+
+	if limit <= 0 || limit > len(addresses) {
+		limit = len(addresses)
+	}
+
+	// Once the soft deadline passes no more addresses are started, and the
+	// race ends with the attempts already in flight.
+	var soft <-chan time.Time
+	stopped := false
+	if cfg.softDeadline > 0 {
+		soft = cfg.timeSource().After(cfg.softDeadline)
+	}
+
+	canLaunch := func() bool {
+		return !stopped && next < len(addresses) && inFlight < limit
+	}
+
+	// With a launch rate every attempt gets the next free launch slot,
+	// launchInterval after the previous one.
+	var nextSlot time.Time
+	launch := func() {
+		i, address := next, addresses[next]
+		next++
+		inFlight++
+		if cfg.report != nil {
+			cfg.report.launched(i, address)
+		}
+
+		var wait time.Duration
+		if cfg.launchInterval > 0 {
+			now := cfg.timeSource().Now()
+			if nextSlot.Before(now) {
+				nextSlot = now
+			}
+
+			wait = nextSlot.Sub(now)
+			nextSlot = nextSlot.Add(cfg.launchInterval)
+		}
+
+		go func() {
+			if wait > 0 || cfg.startDelays != nil || cfg.startJitter > 0 {
+				if err := sleep(ctx, cfg.timeSource(), wait+cfg.startOffset(address)); err != nil {
+					results <- result[T]{index: i, address: address, err: err}
+					return
+				}
+			}
+
+			qcfg := cfg
+			if cfg.budgeting {
+				qcfg.budgetRounds = rounds(len(addresses)-i, limit)
+			}
+
+			start := cfg.timeSource().Now()
+			value, err := query(ctx, getter, address, key, qcfg)
+			latency := cfg.timeSource().Now().Sub(start)
+			results <- result[T]{index: i, address: address, value: value, err: err, latency: latency}
+		}()
+	}
+
+	if cfg.latencies != nil {
+		if d := cfg.latencies.HedgeDelay(); d > 0 {
+			cfg.hedgeDelay = d
+		}
+	}
+
+	initial := limit
+	var hedge <-chan time.Time
+	if cfg.hedgeDelay > 0 && !cfg.sequential {
+		initial = 1
+
+		timer := cfg.timeSource().NewTimer(cfg.hedgeDelay)
+		defer timer.Stop()
+		hedge = timer.C()
+
+		// The timer is re-armed before the attempt starts, so that whoever
+		// sees the attempt also sees the hedge delay counting from it.
+		launchHedged := launch
+		launch = func() {
+			timer.Reset(cfg.hedgeDelay)
+			launchHedged()
+		}
+	}
+
+	for range initial {
+		launch()
+	}
+
+	// launchMore starts the next address, or with hedgeAll all the remaining
+	// ones, as far as the limit allows.
+	launchMore := func() {
+		for canLaunch() {
+			launch()
+			if !cfg.hedgeAll {
+				return
+			}
+		}
+	}
+
+	// Failures are kept in input order, so the first address's error leads
+	// the MultiError and is the first one errors.As finds.
+	errs := make([]AddressError, len(addresses))
+	var candidates []result[T]
+	interrupted := func() (T, string, error) {
+		if cfg.partialOnDeadline && len(candidates) > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return choose(cfg.selector, candidates)
+		}
+
+		return zero, "", canceled(ctx)
+	}
+	// A terminal error ends the race only once minResponses addresses have
+	// answered, so that a slower success can still win over it.
+	var fatal *AddressError
+	responded, minResponses := 0, min(cfg.minResponses, len(addresses))
+	for inFlight > 0 || canLaunch() {
+		select {
+		case r := <-results:
+			inFlight--
+			if answered != nil {
+				answered[r.index] = true
+			}
+			if cfg.report != nil {
+				cfg.report.finished(r.index, r.err, ctx.Err() != nil)
+			}
+
+			if cfg.adaptive != nil && (r.err == nil || ctx.Err() == nil) {
+				cfg.adaptive.Record(r.latency, r.err)
+			}
+
+			if r.err == nil {
+				if cfg.latencies != nil {
+					cfg.latencies.Record(r.latency)
+				}
+
+				if cfg.selector == nil {
+					return r.value, r.address, nil
+				}
+
+				candidates = append(candidates, r)
+			} else {
+				errs[r.index] = AddressError{Address: r.address, Err: r.err}
+				if fatal == nil && ctx.Err() == nil && (cfg.abortOnError || cfg.terminal(r.err)) {
+					fatal = &errs[r.index]
+				}
+			}
+
+			if responded++; fatal != nil && responded >= minResponses {
+				return zero, "", fatal
+			}
+
+			launchMore()
+		case <-hedge:
+			launchMore()
+		case <-soft:
+			stopped = true
+		case <-ctx.Done():
+			return interrupted()
+		}
+	}
+
+	if ctx.Err() != nil {
+		return interrupted()
+	}
+
+	if fatal != nil {
+		return zero, "", fatal
+	}
+
+	if len(candidates) > 0 {
+		return choose(cfg.selector, candidates)
+	}
+
+	for i := next; i < len(addresses); i++ {
+		errs[i] = AddressError{Address: addresses[i], Err: ErrSoftDeadline}
+	}
+
+	if notFound(errs) {
+		return zero, "", ErrKeyNotFound
+	}
+
+	return zero, "", &MultiError{Errors: errs}
+}
+
+// notFound reports whether every address failed with ErrKeyNotFound.
+func notFound(errs []AddressError) bool {
+	for _, e := range errs {
+		if !errors.Is(e.Err, ErrKeyNotFound) {
+			return false
+		}
+	}
+
+	return len(errs) > 0
+}
+
+// awaitLeaks gives the pending attempts still running when an operation
+// ended leakGrace to return, and reports the addresses of those that did not
+// to hook.
+func awaitLeaks[T any](results <-chan result[T], answered []bool, pending int, addresses []string, clock Clock, hook func(address string)) {
+	timer := clock.NewTimer(leakGrace)
+	defer timer.Stop()
+
+	for ; pending > 0; pending-- {
+		select {
+		case r := <-results:
+			answered[r.index] = true
+		case <-timer.C():
+			for i, ok := range answered {
+				if !ok {
+					hook(addresses[i])
+				}
+			}
+			return
+		}
+	}
+}
+
+// checkDeadline fails when ctx is already done or has less than slack left,
+// so no attempt is started that could not finish anyway.
+func checkDeadline(ctx context.Context, slack time.Duration) error {
+	if ctx.Err() != nil {
+		return canceled(ctx)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && slack > 0 {
+		if remaining := time.Until(deadline); remaining < slack {
+			// Like canceled, it matches both Canceled and DeadlineExceeded.
+			return fmt.Errorf("%w: %v left, less than %v needed: %w", context.Canceled, remaining, slack, context.DeadlineExceeded)
+		}
+	}
+
+	return nil
+}
+
+// validate rejects arguments no query could succeed with.
+func validate[T any](getter TypedGetter[T], key string) error {
+	if getter == nil {
+		return fmt.Errorf("invalid arguments: %w", ErrNilGetter)
+	}
+
+	return validateKey(key)
+}
+
+func validateKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("invalid arguments: %w", ErrEmptyKey)
+	}
+
+	return nil
+}
+
+// prepare turns the caller's addresses into the list race actually queries.
+func prepare(addresses []string, key string, cfg config) ([]string, error) {
+	if cfg.resolve != nil {
+		addresses = cfg.resolve(key)
+	}
+
+	// Addresses are launched while the operation runs, so they are read
+	// from a copy that the caller cannot change under them.
+	addresses = slices.Clone(addresses)
+
+	if cfg.onDuplicate != nil {
+		reportDuplicates(addresses, cfg.onDuplicate)
+	}
+
+	if cfg.dedup {
+		addresses = dedup(addresses)
+	}
+
+	if cfg.ring != nil {
+		addresses = cfg.ring.arrange(addresses, key)
+	}
+
+	if cfg.healthy != nil && len(addresses) > 0 {
+		addresses = filterHealthy(addresses, cfg.healthy)
+		if len(addresses) == 0 {
+			return nil, ErrNoHealthyAddresses
+		}
+	}
+
+	if cfg.breaker != nil && len(addresses) > 0 {
+		addresses = cfg.breaker.filter(addresses)
+		if len(addresses) == 0 {
+			return nil, ErrAllCircuitsOpen
+		}
+	}
+
+	if cfg.maxAddresses > 0 && len(addresses) > cfg.maxAddresses {
+		addresses = addresses[:cfg.maxAddresses]
+	}
+
+	return addresses, nil
+}
+
+// reportDuplicates calls hook for every address appearing more than once,
+// in the order of their first appearance.
+func reportDuplicates(addresses []string, hook func(address string, count int)) {
+	counts := make(map[string]int, len(addresses))
+	for _, address := range addresses {
+		counts[address]++
+	}
+
+	for _, address := range addresses {
+		if n := counts[address]; n > 1 {
+			hook(address, n)
+			counts[address] = 0
+		}
+	}
+}
+
+// dedup drops repeated addresses, keeping the first occurrence of each.
+func dedup(addresses []string) []string {
+	seen := make(map[string]struct{}, len(addresses))
+	unique := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if _, ok := seen[address]; !ok {
+			seen[address] = struct{}{}
+			unique = append(unique, address)
+		}
+	}
+
+	return unique
+}
+
+// query fetches key from a single address, retrying failed calls as
+// configured by cfg until one succeeds or ctx is done.
+func query[T any](ctx context.Context, getter TypedGetter[T], address, key string, cfg config) (value T, err error) {
+	attempts := max(cfg.maxAttempts, 1)
+	until := addressBudget(ctx, cfg.budgetRounds)
+	for attempt := range attempts {
+		if attempt > 0 {
+			if err := sleep(ctx, cfg.timeSource(), cfg.backoff(attempt)); err != nil {
+				return value, err
+			}
+		}
+
+		acfg := cfg
+		acfg.attemptTimeout = cfg.timeoutFor(address)
+		if !until.IsZero() {
+			acfg.attemptTimeout = attemptBudget(acfg.attemptTimeout, until, attempts-attempt)
+		}
+
+		if cfg.capAttempts {
+			acfg.attemptTimeout, err = remainingBudget(ctx, acfg.attemptTimeout, cfg.budgetFloor)
+			if err != nil {
+				return value, err
+			}
+		}
+
+		value, err = call(ctx, getter, address, key, acfg)
+		if err == nil || cfg.terminal(err) || errors.Is(err, ErrCircuitOpen) {
+			return value, err
+		}
+	}
+
+	return value, err
+}
+
+// call makes a single getter call, bounded by the per-attempt timeout.
+func call[T any](ctx context.Context, getter TypedGetter[T], address, key string, cfg config) (value T, err error) {
+	parent := ctx
+	if cfg.breaker != nil {
+		ok, probe := cfg.breaker.allow(address)
+		if !ok {
+			return value, ErrCircuitOpen
+		}
+
+		defer func() {
+			// An attempt abandoned because the operation is over says
+			// nothing about the health of its address.
+			switch {
+			case parent.Err() == nil:
+				cfg.breaker.record(address, err)
+			case probe:
+				cfg.breaker.abandon(address)
+			}
+		}()
+	}
+
+	if cfg.slots != nil {
+		select {
+		case cfg.slots <- struct{}{}:
+			defer func() { <-cfg.slots }()
+		case <-ctx.Done():
+			return value, ctx.Err()
+		}
+	}
+
+	if cfg.semaphore != nil {
+		if err := cfg.semaphore.Acquire(ctx, 1); err != nil {
+			return value, fmt.Errorf("semaphore: %w", err)
+		}
+		defer cfg.semaphore.Release(1)
+	}
+
+	if limiter := cfg.limiters[address]; limiter != nil {
+		if err := limiter.Wait(ctx); err != nil {
+			return value, fmt.Errorf("rate limit: %w", err)
+		}
+	}
+
+	if cfg.attemptTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = withTimeout(ctx, cfg.timeSource(), cfg.attemptTimeout)
+		defer cancel()
+	}
+
+	if cfg.tracer != nil {
+		var span Span
+		ctx, span = cfg.tracer.Start(ctx, "attempt", slog.String("address", address), slog.String("key", key))
+		defer func() { endAttempt(parent, span, err) }()
+	}
+
+	if cfg.metrics == nil && cfg.logger == nil {
+		value, err = sharedGet(ctx, getter, address, key, cfg)
+		return value, checkValue(value, err, cfg)
+	}
+
+	cfg.attemptStarted(ctx, address, key)
+	start := cfg.timeSource().Now()
+	value, err = sharedGet(ctx, getter, address, key, cfg)
+	err = checkValue(value, err, cfg)
+	cfg.attemptFinished(parent, address, key, err, cfg.timeSource().Now().Sub(start))
+
+	return value, err
+}
+
+// safeGet calls getter, turning a panic into a PanicError so that a buggy
+// getter fails its own address instead of crashing the program.
+func safeGet[T any](ctx context.Context, getter TypedGetter[T], address, key string) (value T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r}
+		}
+	}()
+
+	return getter.Get(ctx, address, key)
+}
+
+// checkValue turns a successful value into a failure when cfg rejects it:
+// an empty string or byte slice with WithRejectEmpty, or a string the accept
+// func of WithAcceptFunc turns down.
+func checkValue[T any](value T, err error, cfg config) error {
+	if err != nil {
+		return err
+	}
+
+	switch v := any(value).(type) {
+	case string:
+		if cfg.rejectEmpty && v == "" {
+			return ErrEmptyValue
+		}
+
+		if cfg.accept != nil && !cfg.accept(v) {
+			return ErrNoAcceptableValue
+		}
+	case []byte:
+		if cfg.rejectEmpty && len(v) == 0 {
+			return ErrEmptyValue
+		}
+	}
+
+	return nil
+}
+
+// attemptStarted reports the start of a getter call to the configured hooks.
+func (c config) attemptStarted(ctx context.Context, address, key string) {
+	if c.metrics != nil {
+		c.metrics.OnAttemptStart(address, key)
+	}
+
+	if c.logger != nil {
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "attempt started",
+			slog.String("address", address), slog.String("key", key))
+	}
+}
+
+// attemptFinished reports the outcome of a getter call to the configured
+// hooks. ctx is the context of the operation, not of the attempt, so that an
+// attempt timeout counts as a failure rather than a cancellation.
+func (c config) attemptFinished(ctx context.Context, address, key string, err error, elapsed time.Duration) {
+	if c.metrics != nil {
+		if err != nil {
+			c.metrics.OnAttemptError(address, key, err, elapsed)
+		} else {
+			c.metrics.OnAttemptSuccess(address, key, elapsed)
+		}
+	}
+
+	if c.logger == nil {
+		return
+	}
+
+	attrs := []slog.Attr{slog.String("address", address), slog.String("key", key), slog.Duration("elapsed", elapsed)}
+	switch {
+	case err == nil:
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "attempt succeeded", attrs...)
+	case ctx.Err() != nil:
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "attempt cancelled", append(attrs, slog.Any("error", err))...)
+	default:
+		c.logger.LogAttrs(ctx, slog.LevelDebug, "attempt failed", append(attrs, slog.Any("error", err))...)
+	}
+}
+
+// sleep waits for d on clock, returning early with the context error if ctx
+// is done.
+func sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type result[T any] struct {
+	index   int
+	address string
+	value   T
+	err     error
+	latency time.Duration
+}
+
+// canceled builds the error returned when ctx stops the operation. It always
+// matches context.Canceled and, when the context ended for another reason
+// (e.g. its deadline), that reason as well, along with any cause given by
+// context.WithTimeoutCause and friends.
+func canceled(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	if errors.Is(cause, context.Canceled) {
+		return cause
+	}
+
+	if err := ctx.Err(); err != context.Canceled && !errors.Is(cause, err) {
+		cause = fmt.Errorf("%w: %w", err, cause)
+	}
+
+	return fmt.Errorf("%w: %w", context.Canceled, cause)
 }
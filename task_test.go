@@ -3,6 +3,10 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -178,3 +182,1622 @@ func TestGet(t *testing.T) {
 		})
 	}
 }
+
+func TestGetWithSource(t *testing.T) {
+	tests := []struct {
+		name       string
+		responses  map[string]map[string]Response
+		addresses  []string
+		wantValue  string
+		wantSource string
+		wantErr    bool
+	}{
+		{
+			name: "источник — второй адрес",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			addresses:  []string{"addr1", "addr2"},
+			wantValue:  "value2",
+			wantSource: "addr2",
+		},
+		{
+			name: "источник — быстрый адрес",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: 200 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+			},
+			addresses:  []string{"addr1", "addr2"},
+			wantValue:  "value2",
+			wantSource: "addr2",
+		},
+		{
+			name: "все адреса падают — пустой источник",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("error 1")}},
+				"addr2": {"key1": {Error: errors.New("error 2")}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			wantErr:   true,
+		},
+		{
+			name:      "пустой список адресов — пустой источник",
+			responses: map[string]map[string]Response{},
+			addresses: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			value, source, err := GetWithSource(ctx, NewMockGetter(tt.responses), tt.addresses, "key1")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetWithSource() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if value != tt.wantValue || source != tt.wantSource {
+				t.Fatalf("GetWithSource() = (%q, %q), want (%q, %q)", value, source, tt.wantValue, tt.wantSource)
+			}
+		})
+	}
+}
+
+type countingGetter struct {
+	Getter
+
+	mu          sync.Mutex
+	calls       map[string]int
+	inFlight    int
+	maxInFlight int
+}
+
+func newCountingGetter(getter Getter) *countingGetter {
+	return &countingGetter{Getter: getter, calls: map[string]int{}}
+}
+
+func (c *countingGetter) Get(ctx context.Context, address, key string) (string, error) {
+	c.mu.Lock()
+	c.calls[address]++
+	c.inFlight++
+	c.maxInFlight = max(c.maxInFlight, c.inFlight)
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	return c.Getter.Get(ctx, address, key)
+}
+
+func (c *countingGetter) Calls(address string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.calls[address]
+}
+
+func (c *countingGetter) TotalCalls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	total := 0
+	for _, n := range c.calls {
+		total += n
+	}
+
+	return total
+}
+
+func (c *countingGetter) InFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.inFlight
+}
+
+func (c *countingGetter) MaxInFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.maxInFlight
+}
+
+func TestGetLimited(t *testing.T) {
+	slow := func(value string) Response {
+		return Response{Value: value, Delay: 20 * time.Millisecond}
+	}
+	failing := Response{Error: errors.New("connection error"), Delay: 10 * time.Millisecond}
+
+	tests := []struct {
+		name            string
+		responses       map[string]map[string]Response
+		addresses       []string
+		maxConcurrency  int
+		wantValue       string
+		wantErr         bool
+		wantMaxInFlight int
+		wantCalls       int
+	}{
+		{
+			name: "не больше двух запросов одновременно",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": failing},
+				"addr2": {"key1": failing},
+				"addr3": {"key1": failing},
+				"addr4": {"key1": failing},
+				"addr5": {"key1": slow("value5")},
+			},
+			addresses:       []string{"addr1", "addr2", "addr3", "addr4", "addr5"},
+			maxConcurrency:  2,
+			wantValue:       "value5",
+			wantMaxInFlight: 2,
+			wantCalls:       5,
+		},
+		{
+			name: "успех отменяет очередь",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1"}},
+				"addr2": {"key1": slow("value2")},
+				"addr3": {"key1": slow("value3")},
+			},
+			addresses:       []string{"addr1", "addr2", "addr3"},
+			maxConcurrency:  1,
+			wantValue:       "value1",
+			wantMaxInFlight: 1,
+			wantCalls:       1,
+		},
+		{
+			name: "все адреса падают",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": failing},
+				"addr2": {"key1": failing},
+				"addr3": {"key1": failing},
+			},
+			addresses:       []string{"addr1", "addr2", "addr3"},
+			maxConcurrency:  1,
+			wantErr:         true,
+			wantMaxInFlight: 1,
+			wantCalls:       3,
+		},
+		{
+			name: "ноль — без ограничения",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": slow("value1")},
+				"addr2": {"key1": slow("value2")},
+				"addr3": {"key1": slow("value3")},
+			},
+			addresses:       []string{"addr1", "addr2", "addr3"},
+			maxConcurrency:  0,
+			wantMaxInFlight: 3,
+			wantCalls:       3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(tt.responses))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetLimited(ctx, getter, tt.addresses, "key1", tt.maxConcurrency)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetLimited() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantValue != "" && got != tt.wantValue {
+				t.Fatalf("GetLimited() = %q, want %q", got, tt.wantValue)
+			}
+
+			if n := getter.MaxInFlight(); n != tt.wantMaxInFlight {
+				t.Fatalf("max in flight = %d, want %d", n, tt.wantMaxInFlight)
+			}
+
+			if n := getter.TotalCalls(); n != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestGetHedged(t *testing.T) {
+	tests := []struct {
+		name       string
+		responses  map[string]map[string]Response
+		addresses  []string
+		hedgeDelay time.Duration
+		wantValue  string
+		wantErr    bool
+		wantCalls  map[string]int
+	}{
+		{
+			name: "первый адрес отвечает в пределах задержки — второй не запускается",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: 10 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			addresses:  []string{"addr1", "addr2"},
+			hedgeDelay: 100 * time.Millisecond,
+			wantValue:  "value1",
+			wantCalls:  map[string]int{"addr1": 1, "addr2": 0},
+		},
+		{
+			name: "первый адрес медленный — второй запускается после задержки",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: 300 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			addresses:  []string{"addr1", "addr2"},
+			hedgeDelay: 20 * time.Millisecond,
+			wantValue:  "value2",
+			wantCalls:  map[string]int{"addr1": 1, "addr2": 1},
+		},
+		{
+			name: "ошибка запускает следующий адрес без ожидания",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+				"addr2": {"key1": {Value: "value2"}},
+				"addr3": {"key1": {Value: "value3"}},
+			},
+			addresses:  []string{"addr1", "addr2", "addr3"},
+			hedgeDelay: 500 * time.Millisecond,
+			wantValue:  "value2",
+			wantCalls:  map[string]int{"addr1": 1, "addr2": 1, "addr3": 0},
+		},
+		{
+			name: "все адреса падают",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("error 1")}},
+				"addr2": {"key1": {Error: errors.New("error 2")}},
+			},
+			addresses:  []string{"addr1", "addr2"},
+			hedgeDelay: 20 * time.Millisecond,
+			wantErr:    true,
+			wantCalls:  map[string]int{"addr1": 1, "addr2": 1},
+		},
+		{
+			name: "нулевая задержка — все адреса сразу",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: 50 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2", Delay: 10 * time.Millisecond}},
+			},
+			addresses:  []string{"addr1", "addr2"},
+			hedgeDelay: 0,
+			wantValue:  "value2",
+			wantCalls:  map[string]int{"addr1": 1, "addr2": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(tt.responses))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetHedged(ctx, getter, tt.addresses, "key1", tt.hedgeDelay)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetHedged() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetHedged() = %q, want %q", got, tt.wantValue)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithAttemptTimeout(t *testing.T) {
+	tests := []struct {
+		name       string
+		responses  map[string]map[string]Response
+		addresses  []string
+		ttl        time.Duration
+		perAttempt time.Duration
+		wantValue  string
+		wantErr    bool
+		wantErrIs  error
+		maxElapsed time.Duration
+	}{
+		{
+			name: "зависший адрес отваливается по таймауту, второй успешен",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: time.Second}},
+				"addr2": {"key1": {Value: "value2", Delay: 60 * time.Millisecond}},
+			},
+			addresses:  []string{"addr1", "addr2"},
+			ttl:        2 * time.Second,
+			perAttempt: 100 * time.Millisecond,
+			wantValue:  "value2",
+			maxElapsed: 500 * time.Millisecond,
+		},
+		{
+			name: "таймаут попытки считается ошибкой адреса",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: time.Second}},
+				"addr2": {"key1": {Error: errors.New("connection error")}},
+			},
+			addresses:  []string{"addr1", "addr2"},
+			ttl:        2 * time.Second,
+			perAttempt: 30 * time.Millisecond,
+			wantErr:    true,
+			maxElapsed: 500 * time.Millisecond,
+		},
+		{
+			name: "отмена родительского контекста прерывает попытки",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: time.Second}},
+				"addr2": {"key1": {Value: "value2", Delay: time.Second}},
+			},
+			addresses:  []string{"addr1", "addr2"},
+			ttl:        30 * time.Millisecond,
+			perAttempt: 500 * time.Millisecond,
+			wantErr:    true,
+			wantErrIs:  context.Canceled,
+			maxElapsed: 300 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ttl)
+			defer cancel()
+
+			start := time.Now()
+			got, err := GetWithAttemptTimeout(ctx, NewMockGetter(tt.responses), tt.addresses, "key1", tt.perAttempt)
+			elapsed := time.Since(start)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetWithAttemptTimeout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("GetWithAttemptTimeout() error = %v, want errors.Is(err, %v) == true", err, tt.wantErrIs)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetWithAttemptTimeout() = %q, want %q", got, tt.wantValue)
+			}
+
+			if elapsed > tt.maxElapsed {
+				t.Fatalf("GetWithAttemptTimeout() took %v, want at most %v", elapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
+
+func TestGetWithPerAddressTimeout(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"impatient": {"key1": {Value: "value1", Delay: 80 * time.Millisecond}},
+		"patient":   {"key1": {Value: "value2", Delay: 80 * time.Millisecond}},
+		"default":   {"key1": {Value: "value3", Delay: 80 * time.Millisecond}},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	got, stats, err := GetWithStats(ctx, mock, []string{"impatient", "patient", "default"}, "key1",
+		WithAttemptTimeout(20*time.Millisecond),
+		WithPerAddressTimeout(map[string]time.Duration{
+			"impatient": 10 * time.Millisecond,
+			"patient":   500 * time.Millisecond,
+		}))
+	if err != nil || got != "value2" {
+		t.Fatalf("GetWithStats() = (%q, %v), want (%q, nil)", got, err, "value2")
+	}
+
+	want := map[string]Outcome{"impatient": OutcomeFailure, "patient": OutcomeSuccess, "default": OutcomeFailure}
+	for _, s := range stats {
+		if s.Outcome != want[s.Address] {
+			t.Fatalf("%s finished with %v, want %v", s.Address, s.Outcome, want[s.Address])
+		}
+	}
+}
+
+func TestGetJoinsErrors(t *testing.T) {
+	errConn := errors.New("connection error")
+	errTimeout := errors.New("timeout")
+
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errConn}},
+		"addr2": {"key1": {Error: errTimeout, Delay: 10 * time.Millisecond}},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := Get(ctx, mock, []string{"addr1", "addr2"}, "key1")
+	if err == nil {
+		t.Fatal("Get() error = nil, want joined error")
+	}
+
+	for _, want := range []error{errConn, errTimeout} {
+		if !errors.Is(err, want) {
+			t.Fatalf("Get() error = %v, want errors.Is(err, %v) == true", err, want)
+		}
+	}
+
+	for _, want := range []string{"addr1: connection error", "addr2: timeout"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Fatalf("Get() error = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+type flakyGetter struct {
+	mu       sync.Mutex
+	failures map[string]int
+	delay    time.Duration
+	calls    map[string]int
+}
+
+func newFlakyGetter(failures map[string]int, delay time.Duration) *flakyGetter {
+	return &flakyGetter{failures: failures, delay: delay, calls: map[string]int{}}
+}
+
+func (f *flakyGetter) Get(ctx context.Context, address, key string) (string, error) {
+	f.mu.Lock()
+	f.calls[address]++
+	call := f.calls[address]
+	f.mu.Unlock()
+
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	if failures, ok := f.failures[address]; ok && (failures < 0 || call <= failures) {
+		return "", errors.New("connection reset")
+	}
+
+	return "value-" + address, nil
+}
+
+func (f *flakyGetter) Calls(address string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.calls[address]
+}
+
+func TestGetWithRetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		failures    map[string]int
+		addresses   []string
+		maxAttempts int
+		wantValue   string
+		wantErr     bool
+		wantCalls   map[string]int
+	}{
+		{
+			name:        "повтор после временной ошибки",
+			failures:    map[string]int{"addr1": 2},
+			addresses:   []string{"addr1"},
+			maxAttempts: 3,
+			wantValue:   "value-addr1",
+			wantCalls:   map[string]int{"addr1": 3},
+		},
+		{
+			name:        "попытки исчерпаны",
+			failures:    map[string]int{"addr1": 2},
+			addresses:   []string{"addr1"},
+			maxAttempts: 2,
+			wantErr:     true,
+			wantCalls:   map[string]int{"addr1": 2},
+		},
+		{
+			name:        "одна попытка — как обычный Get",
+			failures:    map[string]int{"addr1": 1, "addr2": 1},
+			addresses:   []string{"addr1", "addr2"},
+			maxAttempts: 1,
+			wantErr:     true,
+			wantCalls:   map[string]int{"addr1": 1, "addr2": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newFlakyGetter(tt.failures, 0)
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetWithRetry(ctx, getter, tt.addresses, "key1", tt.maxAttempts)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetWithRetry() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetWithRetry() = %q, want %q", got, tt.wantValue)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithRetryStopsAfterSuccess(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection reset"), Delay: 10 * time.Millisecond}},
+		"addr2": {"key1": {Value: "value2", Delay: 35 * time.Millisecond}},
+	})
+	getter := newCountingGetter(mock)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	got, err := GetWithRetry(ctx, getter, []string{"addr1", "addr2"}, "key1", 100)
+	if err != nil || got != "value2" {
+		t.Fatalf("GetWithRetry() = (%q, %v), want (%q, nil)", got, err, "value2")
+	}
+
+	calls := getter.Calls("addr1")
+	time.Sleep(50 * time.Millisecond)
+
+	if n := getter.Calls("addr1"); n != calls {
+		t.Fatalf("addr1 kept retrying after success: %d calls, then %d", calls, n)
+	}
+}
+
+func TestGetWithBackoff(t *testing.T) {
+	tests := []struct {
+		name        string
+		base        time.Duration
+		maxBackoff  time.Duration
+		maxAttempts int
+		opts        []Option
+		ttl         time.Duration
+		wantErrIs   error
+		wantCalls   int
+		minElapsed  time.Duration
+		maxElapsed  time.Duration
+	}{
+		{
+			name:        "экспоненциальная задержка с потолком",
+			base:        10 * time.Millisecond,
+			maxBackoff:  25 * time.Millisecond,
+			maxAttempts: 4,
+			ttl:         time.Second,
+			wantCalls:   4,
+			minElapsed:  55 * time.Millisecond,
+			maxElapsed:  300 * time.Millisecond,
+		},
+		{
+			name:        "джиттер не превышает расчётную задержку",
+			base:        10 * time.Millisecond,
+			maxBackoff:  25 * time.Millisecond,
+			maxAttempts: 4,
+			opts:        []Option{WithJitter()},
+			ttl:         time.Second,
+			wantCalls:   4,
+			maxElapsed:  300 * time.Millisecond,
+		},
+		{
+			name:        "ожидание прерывается отменой контекста",
+			base:        time.Second,
+			maxBackoff:  time.Second,
+			maxAttempts: 3,
+			ttl:         50 * time.Millisecond,
+			wantErrIs:   context.Canceled,
+			wantCalls:   1,
+			maxElapsed:  300 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newFlakyGetter(map[string]int{"addr1": -1}, 0)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ttl)
+			defer cancel()
+
+			start := time.Now()
+			_, err := GetWithBackoff(ctx, getter, []string{"addr1"}, "key1", tt.base, tt.maxBackoff, tt.maxAttempts, tt.opts...)
+			elapsed := time.Since(start)
+
+			if err == nil {
+				t.Fatal("GetWithBackoff() error = nil, want error")
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("GetWithBackoff() error = %v, want errors.Is(err, %v) == true", err, tt.wantErrIs)
+			}
+
+			if n := getter.Calls("addr1"); n != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", n, tt.wantCalls)
+			}
+
+			if elapsed < tt.minElapsed || elapsed > tt.maxElapsed {
+				t.Fatalf("GetWithBackoff() took %v, want between %v and %v", elapsed, tt.minElapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
+
+func TestGetNoGoroutineLeak(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1", Delay: 10 * time.Second}},
+		"addr2": {"key1": {Value: "value2", Delay: 10 * time.Second}},
+		"addr3": {"key1": {Value: "value3", Delay: 10 * time.Millisecond}},
+	})
+	getter := newCountingGetter(mock)
+
+	got, err := Get(context.Background(), getter, []string{"addr1", "addr2", "addr3"}, "key1")
+	if err != nil || got != "value3" {
+		t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value3")
+	}
+
+	deadline := time.Now().Add(100 * time.Millisecond)
+	for getter.InFlight() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d getter calls still running after Get returned", getter.InFlight())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestGetWithLatency(t *testing.T) {
+	tests := []struct {
+		name       string
+		responses  map[string]map[string]Response
+		addresses  []string
+		wantValue  string
+		wantErr    bool
+		minLatency time.Duration
+		maxLatency time.Duration
+	}{
+		{
+			name: "задержка победившего ответа",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: 200 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2", Delay: 40 * time.Millisecond}},
+			},
+			addresses:  []string{"addr1", "addr2"},
+			wantValue:  "value2",
+			minLatency: 40 * time.Millisecond,
+			maxLatency: 150 * time.Millisecond,
+		},
+		{
+			name: "при ошибке — время до последнего отказа",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("error 1"), Delay: 10 * time.Millisecond}},
+				"addr2": {"key1": {Error: errors.New("error 2"), Delay: 60 * time.Millisecond}},
+			},
+			addresses:  []string{"addr1", "addr2"},
+			wantErr:    true,
+			minLatency: 60 * time.Millisecond,
+			maxLatency: 200 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, latency, err := GetWithLatency(ctx, NewMockGetter(tt.responses), tt.addresses, "key1")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetWithLatency() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetWithLatency() = %q, want %q", got, tt.wantValue)
+			}
+
+			if latency < tt.minLatency || latency > tt.maxLatency {
+				t.Fatalf("GetWithLatency() latency = %v, want between %v and %v", latency, tt.minLatency, tt.maxLatency)
+			}
+		})
+	}
+}
+
+func TestGetInvalidInput(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1"}},
+	})
+
+	tests := []struct {
+		name      string
+		getter    Getter
+		addresses []string
+		key       string
+		wantErrIs error
+	}{
+		{
+			name:      "nil getter",
+			getter:    nil,
+			addresses: []string{"addr1"},
+			key:       "key1",
+			wantErrIs: ErrNilGetter,
+		},
+		{
+			name:      "пустой ключ",
+			getter:    mock,
+			addresses: []string{"addr1"},
+			key:       "",
+			wantErrIs: ErrEmptyKey,
+		},
+		{
+			name:      "nil getter и пустой список адресов",
+			getter:    nil,
+			addresses: []string{},
+			key:       "key1",
+			wantErrIs: ErrNilGetter,
+		},
+		{
+			name:      "пустой список адресов остаётся допустимым",
+			getter:    mock,
+			addresses: []string{},
+			key:       "key1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Get(context.Background(), tt.getter, tt.addresses, tt.key)
+
+			if !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("Get() error = %v, want errors.Is(err, %v) == true", err, tt.wantErrIs)
+			}
+
+			if got != "" {
+				t.Fatalf("Get() = %q, want empty", got)
+			}
+		})
+	}
+}
+
+type codeError struct {
+	code int
+}
+
+func (e *codeError) Error() string {
+	return fmt.Sprintf("code %d", e.code)
+}
+
+func TestGetFirstErrorLeads(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: &codeError{code: 1}, Delay: 40 * time.Millisecond}},
+		"addr2": {"key1": {Error: &codeError{code: 2}}},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := Get(ctx, mock, []string{"addr1", "addr2"}, "key1")
+
+	var ce *codeError
+	if !errors.As(err, &ce) || ce.code != 1 {
+		t.Fatalf("Get() error = %v, want errors.As to find the error of addr1", err)
+	}
+
+	if !strings.HasPrefix(err.Error(), "addr1: code 1") {
+		t.Fatalf("Get() error = %q, want it to start with the error of addr1", err)
+	}
+}
+
+type requestIDKey struct{}
+
+type contextRecordingGetter struct {
+	Getter
+
+	mu   sync.Mutex
+	seen []any
+}
+
+func (g *contextRecordingGetter) Get(ctx context.Context, address, key string) (string, error) {
+	g.mu.Lock()
+	g.seen = append(g.seen, ctx.Value(requestIDKey{}))
+	g.mu.Unlock()
+
+	return g.Getter.Get(ctx, address, key)
+}
+
+func TestGetPropagatesContextValues(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection error")}},
+		"addr2": {"key1": {Error: errors.New("connection error")}},
+		"addr3": {"key1": {Value: "value3", Delay: 20 * time.Millisecond}},
+	})
+	getter := &contextRecordingGetter{Getter: mock}
+
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), requestIDKey{}, "req-42"), time.Second)
+	defer cancel()
+
+	opts := []Option{WithRetry(2), WithAttemptTimeout(500 * time.Millisecond), WithHedgeDelay(5 * time.Millisecond)}
+	if _, err := Get(ctx, getter, []string{"addr1", "addr2", "addr3"}, "key1", opts...); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	getter.mu.Lock()
+	defer getter.mu.Unlock()
+
+	if len(getter.seen) < 5 {
+		t.Fatalf("getter called %d times, want at least 5", len(getter.seen))
+	}
+
+	for i, v := range getter.seen {
+		if v != "req-42" {
+			t.Fatalf("call %d saw request ID %v, want %q", i, v, "req-42")
+		}
+	}
+}
+
+func TestGetDeadlineAbort(t *testing.T) {
+	tests := []struct {
+		name      string
+		ctx       func() (context.Context, context.CancelFunc)
+		opts      []Option
+		wantErrIs error
+		wantCalls int
+	}{
+		{
+			name: "контекст уже истёк",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+			},
+			wantErrIs: context.DeadlineExceeded,
+		},
+		{
+			name: "контекст уже отменён",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			wantErrIs: context.Canceled,
+		},
+		{
+			name: "времени меньше запаса",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			opts:      []Option{WithDeadlineSlack(50 * time.Millisecond)},
+			wantErrIs: context.DeadlineExceeded,
+		},
+		{
+			name: "времени достаточно",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			opts:      []Option{WithDeadlineSlack(50 * time.Millisecond)},
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockGetter(map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1"}},
+			})
+			getter := newCountingGetter(mock)
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			_, err := Get(ctx, getter, []string{"addr1"}, "key1", tt.opts...)
+
+			if !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("Get() error = %v, want %v", err, tt.wantErrIs)
+			}
+
+			// Expired and nearly expired contexts are reported alike.
+			if tt.wantErrIs != nil && !errors.Is(err, context.Canceled) {
+				t.Fatalf("Get() error = %v, want it to match %v as well", err, context.Canceled)
+			}
+
+			if n := getter.TotalCalls(); n != tt.wantCalls {
+				t.Fatalf("calls = %d, want %d", n, tt.wantCalls)
+			}
+		})
+	}
+
+	t.Run("истёкший контекст не доходит до подготовки адресов", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		prepared := 0
+		opts := []Option{
+			WithAddressResolver(func(string) []string { prepared++; return []string{"addr1"} }),
+			WithHealthChecker(func(string) bool { prepared++; return true }),
+			WithDuplicateAddressHook(func(string, int) { prepared++ }),
+		}
+
+		if _, err := Get(ctx, NewMockGetter(nil), []string{"addr1", "addr1"}, "key1", opts...); !errors.Is(err, context.Canceled) {
+			t.Fatalf("Get() error = %v, want %v", err, context.Canceled)
+		}
+
+		if prepared != 0 {
+			t.Fatalf("resolver, health checker and hook called %d times, want none", prepared)
+		}
+	})
+}
+
+func TestGetCancellationCause(t *testing.T) {
+	errShutdown := errors.New("shutting down")
+	mock := NewMockGetter(map[string]map[string]Response{
+		"fast": {"key1": {Value: "value1", Delay: 10 * time.Millisecond}},
+		"slow": {"key1": {Value: "value2", Delay: time.Second}},
+	})
+
+	tests := []struct {
+		name         string
+		ctx          func() (context.Context, context.CancelFunc)
+		addresses    []string
+		want         string
+		wantErrIs    []error
+		wantErrIsNot []error
+	}{
+		{
+			name: "победитель отменяет остальных без ошибки",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), time.Second)
+			},
+			addresses: []string{"slow", "fast"},
+			want:      "value1",
+		},
+		{
+			name: "дедлайн",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 30*time.Millisecond)
+			},
+			addresses: []string{"slow"},
+			wantErrIs: []error{context.DeadlineExceeded, context.Canceled},
+		},
+		{
+			name: "явная отмена",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(30*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			addresses:    []string{"slow"},
+			wantErrIs:    []error{context.Canceled},
+			wantErrIsNot: []error{context.DeadlineExceeded},
+		},
+		{
+			name: "отмена с причиной",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancelCause(context.Background())
+				time.AfterFunc(30*time.Millisecond, func() { cancel(errShutdown) })
+				return ctx, func() { cancel(nil) }
+			},
+			addresses:    []string{"slow"},
+			wantErrIs:    []error{errShutdown, context.Canceled},
+			wantErrIsNot: []error{context.DeadlineExceeded},
+		},
+		{
+			name: "дедлайн с причиной",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeoutCause(context.Background(), 30*time.Millisecond, errShutdown)
+			},
+			addresses: []string{"slow"},
+			wantErrIs: []error{errShutdown, context.DeadlineExceeded, context.Canceled},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			got, err := Get(ctx, mock, tt.addresses, "key1")
+			if got != tt.want {
+				t.Fatalf("Get() = %q, want %q", got, tt.want)
+			}
+
+			if len(tt.wantErrIs) == 0 && err != nil {
+				t.Fatalf("Get() error = %v, want nil", err)
+			}
+
+			for _, want := range tt.wantErrIs {
+				if !errors.Is(err, want) {
+					t.Fatalf("Get() error = %v, want errors.Is(err, %v) == true", err, want)
+				}
+			}
+
+			for _, unwanted := range tt.wantErrIsNot {
+				if errors.Is(err, unwanted) {
+					t.Fatalf("Get() error = %v, want errors.Is(err, %v) == false", err, unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestGetWithTimeout(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1", Delay: time.Second}},
+		"addr2": {"key1": {Value: "value2", Delay: time.Second}},
+	})
+
+	tests := []struct {
+		name        string
+		ctx         func() (context.Context, context.CancelFunc)
+		timeout     time.Duration
+		wantErrIs   error
+		wantTimeout bool
+	}{
+		{
+			name: "истекло собственное время",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			timeout:     30 * time.Millisecond,
+			wantErrIs:   ErrTimeout,
+			wantTimeout: true,
+		},
+		{
+			name: "вызывающий отменил раньше",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			timeout:   500 * time.Millisecond,
+			wantErrIs: context.Canceled,
+		},
+		{
+			name: "дедлайн вызывающего наступил раньше",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 20*time.Millisecond)
+			},
+			timeout:   500 * time.Millisecond,
+			wantErrIs: context.DeadlineExceeded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			got, err := GetWithTimeout(ctx, mock, []string{"addr1", "addr2"}, "key1", tt.timeout)
+
+			if got != "" || !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("GetWithTimeout() = (%q, %v), want errors.Is(err, %v) == true", got, err, tt.wantErrIs)
+			}
+
+			if errors.Is(err, ErrTimeout) != tt.wantTimeout {
+				t.Fatalf("GetWithTimeout() error = %v, want errors.Is(err, ErrTimeout) == %v", err, tt.wantTimeout)
+			}
+		})
+	}
+}
+
+type panickingGetter struct {
+	Getter
+
+	panics map[string]any
+}
+
+func (p *panickingGetter) Get(ctx context.Context, address, key string) (string, error) {
+	if v, ok := p.panics[address]; ok {
+		panic(v)
+	}
+
+	return p.Getter.Get(ctx, address, key)
+}
+
+func TestGetRecoversGetterPanic(t *testing.T) {
+	errBug := errors.New("nil map write")
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr2": {"key1": {Value: "value2", Delay: 10 * time.Millisecond}},
+	})
+
+	tests := []struct {
+		name      string
+		panics    map[string]any
+		opts      []Option
+		wantValue string
+		wantErrIs error
+	}{
+		{
+			name:      "один адрес паникует, другой успешен",
+			panics:    map[string]any{"addr1": "index out of range"},
+			wantValue: "value2",
+		},
+		{
+			name:      "паника с повторами",
+			panics:    map[string]any{"addr1": "index out of range"},
+			opts:      []Option{WithRetry(3), WithMetrics(&recordingMetrics{})},
+			wantValue: "value2",
+		},
+		{
+			name:      "все адреса паникуют",
+			panics:    map[string]any{"addr1": errBug, "addr2": errBug},
+			wantErrIs: errBug,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := &panickingGetter{Getter: mock, panics: tt.panics}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := Get(ctx, getter, []string{"addr1", "addr2"}, "key1", tt.opts...)
+
+			if got != tt.wantValue || !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("Get() = (%q, %v), want (%q, %v)", got, err, tt.wantValue, tt.wantErrIs)
+			}
+
+			var panicErr *PanicError
+			if tt.wantErrIs != nil && !errors.As(err, &panicErr) {
+				t.Fatalf("Get() error = %v, want a *PanicError", err)
+			}
+		})
+	}
+}
+
+func TestGetWithStop(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Value: "value1", Delay: time.Second}},
+		"addr2": {"key1": {Value: "value2", Delay: 10 * time.Millisecond}},
+	})
+
+	t.Run("закрытие канала прерывает медленную попытку", func(t *testing.T) {
+		stop := make(chan struct{})
+		time.AfterFunc(20*time.Millisecond, func() { close(stop) })
+
+		start := time.Now()
+		got, err := GetWithStop(context.Background(), mock, []string{"addr1"}, "key1", stop)
+		elapsed := time.Since(start)
+
+		if got != "" || !errors.Is(err, context.Canceled) || !errors.Is(err, ErrStopped) {
+			t.Fatalf("GetWithStop() = (%q, %v), want context.Canceled and ErrStopped", got, err)
+		}
+
+		if elapsed > 200*time.Millisecond {
+			t.Fatalf("GetWithStop() took %v, want it to abort soon after stop is closed", elapsed)
+		}
+	})
+
+	t.Run("канал не закрыт", func(t *testing.T) {
+		got, err := GetWithStop(context.Background(), mock, []string{"addr1", "addr2"}, "key1", make(chan struct{}))
+		if err != nil || got != "value2" {
+			t.Fatalf("GetWithStop() = (%q, %v), want (%q, nil)", got, err, "value2")
+		}
+	})
+
+	t.Run("отмена контекста", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		_, err := GetWithStop(ctx, mock, []string{"addr1"}, "key1", nil)
+		if !errors.Is(err, context.Canceled) || errors.Is(err, ErrStopped) {
+			t.Fatalf("GetWithStop() error = %v, want context.Canceled without ErrStopped", err)
+		}
+	})
+}
+
+func TestGetWithSoftDeadline(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		wantValue string
+		wantErrIs error
+		wantCalls map[string]int
+	}{
+		{
+			name: "попытка, начатая до мягкого дедлайна, завершается в отсрочке",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error"), Delay: 40 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2", Delay: 30 * time.Millisecond}},
+			},
+			wantValue: "value2",
+			wantCalls: map[string]int{"addr1": 1, "addr2": 1},
+		},
+		{
+			name: "попытка не укладывается в отсрочку",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error"), Delay: 40 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2", Delay: time.Second}},
+			},
+			wantErrIs: context.DeadlineExceeded,
+			wantCalls: map[string]int{"addr1": 1, "addr2": 1},
+		},
+		{
+			name: "после мягкого дедлайна новые адреса не запускаются",
+			responses: map[string]map[string]Response{
+				"addr1": {"key1": {Error: errors.New("connection error"), Delay: 70 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2"}},
+			},
+			wantErrIs: ErrSoftDeadline,
+			wantCalls: map[string]int{"addr1": 1, "addr2": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(tt.responses))
+
+			got, err := GetWithSoftDeadline(context.Background(), getter, []string{"addr1", "addr2"}, "key1",
+				50*time.Millisecond, 50*time.Millisecond, WithMaxConcurrency(1))
+
+			if got != tt.wantValue || !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("GetWithSoftDeadline() = (%q, %v), want (%q, %v)", got, err, tt.wantValue, tt.wantErrIs)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGetSimple(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection error")}},
+		"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+		"slow":  {"key1": {Value: "slow", Delay: 200 * time.Millisecond}},
+	})
+
+	tests := []struct {
+		name      string
+		addresses []string
+		timeout   time.Duration
+		wantValue string
+		wantErrIs error
+	}{
+		{name: "как Get", addresses: []string{"addr1", "addr2"}, timeout: time.Second, wantValue: "value2"},
+		{name: "таймаут применяется", addresses: []string{"slow"}, timeout: 30 * time.Millisecond, wantErrIs: context.DeadlineExceeded},
+		{name: "нулевой таймаут — без дедлайна", addresses: []string{"slow"}, wantValue: "slow"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetSimple(mock, tt.addresses, "key1", tt.timeout)
+
+			if got != tt.wantValue || !errors.Is(err, tt.wantErrIs) || (tt.wantErrIs == nil && err != nil) {
+				t.Fatalf("GetSimple() = (%q, %v), want (%q, %v)", got, err, tt.wantValue, tt.wantErrIs)
+			}
+
+			if tt.wantErrIs == nil {
+				want, wantErr := Get(context.Background(), mock, tt.addresses, "key1")
+				if got != want || (err == nil) != (wantErr == nil) {
+					t.Fatalf("GetSimple() = (%q, %v), Get() = (%q, %v)", got, err, want, wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestGetPrimaryThenFanout(t *testing.T) {
+	addresses := []string{"primary", "secondary1", "secondary2"}
+
+	tests := []struct {
+		name            string
+		responses       map[string]map[string]Response
+		wantValues      []string
+		wantCalls       map[string]int
+		wantMaxInFlight int
+		maxElapsed      time.Duration
+	}{
+		{
+			name: "основной отвечает в пределах форы",
+			responses: map[string]map[string]Response{
+				"primary":    {"key1": {Value: "primary", Delay: 10 * time.Millisecond}},
+				"secondary1": {"key1": {Value: "secondary1"}},
+				"secondary2": {"key1": {Value: "secondary2"}},
+			},
+			wantValues:      []string{"primary"},
+			wantCalls:       map[string]int{"primary": 1, "secondary1": 0, "secondary2": 0},
+			wantMaxInFlight: 1,
+			maxElapsed:      100 * time.Millisecond,
+		},
+		{
+			name: "основной медлит — все запасные разом",
+			responses: map[string]map[string]Response{
+				"primary":    {"key1": {Value: "primary", Delay: time.Second}},
+				"secondary1": {"key1": {Value: "secondary1", Delay: 30 * time.Millisecond}},
+				"secondary2": {"key1": {Value: "secondary2", Delay: 30 * time.Millisecond}},
+			},
+			wantValues:      []string{"secondary1", "secondary2"},
+			wantCalls:       map[string]int{"primary": 1, "secondary1": 1, "secondary2": 1},
+			wantMaxInFlight: 3,
+			maxElapsed:      300 * time.Millisecond,
+		},
+		{
+			name: "основной падает — запасные без ожидания форы",
+			responses: map[string]map[string]Response{
+				"primary":    {"key1": {Error: errors.New("connection error")}},
+				"secondary1": {"key1": {Value: "secondary1", Delay: 10 * time.Millisecond}},
+				"secondary2": {"key1": {Value: "secondary2", Delay: 10 * time.Millisecond}},
+			},
+			wantValues:      []string{"secondary1", "secondary2"},
+			wantCalls:       map[string]int{"primary": 1, "secondary1": 1, "secondary2": 1},
+			wantMaxInFlight: 2,
+			maxElapsed:      55 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(tt.responses))
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+
+			start := time.Now()
+			got, err := GetPrimaryThenFanout(ctx, getter, addresses, "key1", 50*time.Millisecond)
+			elapsed := time.Since(start)
+
+			if err != nil || !slices.Contains(tt.wantValues, got) {
+				t.Fatalf("GetPrimaryThenFanout() = (%q, %v), want one of %v", got, err, tt.wantValues)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+
+			if n := getter.MaxInFlight(); n != tt.wantMaxInFlight {
+				t.Fatalf("max in flight = %d, want %d", n, tt.wantMaxInFlight)
+			}
+
+			if elapsed > tt.maxElapsed {
+				t.Fatalf("GetPrimaryThenFanout() took %v, want at most %v", elapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
+
+// gatedGetter announces every call on started and fails it once release is
+// closed.
+type gatedGetter struct {
+	started chan string
+	release chan struct{}
+}
+
+func (g gatedGetter) Get(ctx context.Context, address, key string) (string, error) {
+	g.started <- address
+	<-g.release
+
+	return "", fmt.Errorf("%s: connection error", address)
+}
+
+func TestGetCopiesAddresses(t *testing.T) {
+	getter := gatedGetter{started: make(chan string, 2), release: make(chan struct{})}
+	addresses := []string{"addr1", "addr2"}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := Get(context.Background(), getter, addresses, "key1", WithMaxConcurrency(1))
+		done <- err
+	}()
+
+	if got := <-getter.started; got != "addr1" {
+		t.Fatalf("first call to %s, want addr1", got)
+	}
+
+	// Get is running, and addr2 has not been launched yet.
+	addresses[1] = "mutated"
+	close(getter.release)
+
+	if got := <-getter.started; got != "addr2" {
+		t.Fatalf("second call to %s, want addr2", got)
+	}
+
+	if err := <-done; err == nil {
+		t.Fatal("Get() error = nil, want every address to fail")
+	}
+}
+
+func TestGetKeyNotFound(t *testing.T) {
+	errConn := errors.New("connection error")
+	getter := &MapGetter{
+		Responses: map[string]map[string]string{
+			"addr1": {"key1": "value1"},
+			"addr2": {},
+		},
+		Errors: map[string]error{"down": errConn},
+	}
+
+	tests := []struct {
+		name         string
+		addresses    []string
+		key          string
+		want         string
+		wantNotFound bool
+		wantErrIs    error
+	}{
+		{name: "ключа нет нигде", addresses: []string{"addr1", "addr2"}, key: "missing", wantNotFound: true},
+		{name: "ключа нет, один адрес недоступен", addresses: []string{"addr1", "down"}, key: "missing", wantErrIs: errConn},
+		{name: "ключ есть хотя бы на одном", addresses: []string{"addr2", "addr1"}, key: "key1", want: "value1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Get(context.Background(), getter, tt.addresses, tt.key)
+			if got != tt.want {
+				t.Fatalf("Get() = %q, want %q", got, tt.want)
+			}
+
+			var multiErr *MultiError
+			switch {
+			case tt.wantNotFound:
+				if err != ErrKeyNotFound {
+					t.Fatalf("Get() error = %v, want %v", err, ErrKeyNotFound)
+				}
+			case tt.wantErrIs != nil:
+				if !errors.As(err, &multiErr) || !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("Get() error = %v, want a MultiError surfacing %v", err, tt.wantErrIs)
+				}
+			case err != nil:
+				t.Fatalf("Get() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+// causeRecordingGetter answers at once for fast and otherwise waits for ctx,
+// sending the cause of its cancellation on causes.
+type causeRecordingGetter struct {
+	causes chan error
+}
+
+func (g causeRecordingGetter) Get(ctx context.Context, address, key string) (string, error) {
+	if address == "fast" {
+		return "value-fast", nil
+	}
+
+	<-ctx.Done()
+	g.causes <- context.Cause(ctx)
+	return "", ctx.Err()
+}
+
+func TestGetCancellationCauseSeenByGetter(t *testing.T) {
+	errShutdown := errors.New("shutting down")
+
+	tests := []struct {
+		name      string
+		addresses []string
+		ctx       func() (context.Context, context.CancelFunc)
+		wantCause error
+	}{
+		{
+			name:      "проигравший видит ErrWinnerFound",
+			addresses: []string{"slow", "fast"},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithCancel(context.Background())
+			},
+			wantCause: ErrWinnerFound,
+		},
+		{
+			name:      "причина вызывающего",
+			addresses: []string{"slow"},
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancelCause(context.Background())
+				time.AfterFunc(20*time.Millisecond, func() { cancel(errShutdown) })
+				return ctx, func() { cancel(nil) }
+			},
+			wantCause: errShutdown,
+		},
+		{
+			name:      "обычная отмена вызывающим",
+			addresses: []string{"slow"},
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				time.AfterFunc(20*time.Millisecond, cancel)
+				return ctx, cancel
+			},
+			wantCause: context.Canceled,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := causeRecordingGetter{causes: make(chan error, 1)}
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			if _, err := Get(ctx, getter, tt.addresses, "key1"); errors.Is(err, ErrWinnerFound) {
+				t.Fatalf("Get() error = %v, want the winner-found cause never returned", err)
+			}
+
+			select {
+			case cause := <-getter.causes:
+				if cause != tt.wantCause {
+					t.Fatalf("getter saw cause %v, want %v", cause, tt.wantCause)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("slow getter was never cancelled")
+			}
+		})
+	}
+}
+
+// stuckGetter ignores ctx: calls to stuck block until release is closed,
+// the others answer at once.
+type stuckGetter struct {
+	release chan struct{}
+}
+
+func (g stuckGetter) Get(ctx context.Context, address, key string) (string, error) {
+	if address == "stuck" {
+		<-g.release
+		return "", errors.New("too late")
+	}
+
+	return "value-" + address, nil
+}
+
+func TestGetWithLeakHook(t *testing.T) {
+	tests := []struct {
+		name      string
+		addresses []string
+		ttl       time.Duration
+		want      string
+		wantLeaks []string
+	}{
+		{name: "победитель не ждёт зависший getter", addresses: []string{"stuck", "fast"}, ttl: time.Second, want: "value-fast", wantLeaks: []string{"stuck"}},
+		{name: "дедлайн не ждёт зависший getter", addresses: []string{"stuck"}, ttl: 30 * time.Millisecond, wantLeaks: []string{"stuck"}},
+		{name: "без зависших хук молчит", addresses: []string{"fast"}, ttl: time.Second, want: "value-fast"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := stuckGetter{release: make(chan struct{})}
+			defer close(getter.release)
+
+			leaks := make(chan string, len(tt.addresses))
+			hook := func(address string) { leaks <- address }
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ttl)
+			defer cancel()
+
+			start := time.Now()
+			got, _ := Get(ctx, getter, tt.addresses, "key1", WithLeakHook(hook))
+			if elapsed := time.Since(start); got != tt.want || elapsed > tt.ttl+50*time.Millisecond {
+				t.Fatalf("Get() = %q after %v, want %q within %v", got, elapsed, tt.want, tt.ttl)
+			}
+
+			var reported []string
+			timeout := time.After(leakGrace + 200*time.Millisecond)
+		collect:
+			for {
+				select {
+				case address := <-leaks:
+					reported = append(reported, address)
+				case <-timeout:
+					break collect
+				}
+			}
+
+			if !slices.Equal(reported, tt.wantLeaks) {
+				t.Fatalf("leak hook reported %v, want %v", reported, tt.wantLeaks)
+			}
+		})
+	}
+}
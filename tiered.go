@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// GetTiered races the addresses of one tier at a time, moving on to the next
+// tier only when every address of the current one failed. Later tiers are
+// never started once an earlier one succeeds. When all tiers fail, the
+// returned MultiError holds the failures of every tier; errors that end the
+// operation, such as a done ctx or a terminal error, are returned at once.
+func GetTiered(ctx context.Context, getter Getter, tiers [][]string, key string, opts ...Option) (string, error) {
+	groups := make([]tier, len(tiers))
+	for i, addresses := range tiers {
+		groups[i] = tier{addresses: addresses}
+	}
+
+	value, _, err := raceTiers(ctx, getter, groups, key, newConfig(opts))
+	return value, err
+}
+
+// tier is a group of addresses raced together, each started after its
+// delay in startDelays when that is set.
+type tier struct {
+	addresses   []string
+	startDelays map[string]time.Duration
+}
+
+// resolveTiers applies the resolver of cfg, if any, to tiers once for the
+// whole operation, as described by WithAddressResolver, and returns cfg
+// without it so that the tiers are not resolved again one by one.
+func resolveTiers(tiers []tier, key string, cfg config) ([]tier, config) {
+	if cfg.resolve == nil {
+		return tiers, cfg
+	}
+
+	resolved := cfg.resolve(key)
+	cfg.resolve = nil
+	if len(tiers) <= 1 {
+		t := tier{addresses: resolved}
+		if len(tiers) == 1 {
+			t.startDelays = tiers[0].startDelays
+		}
+
+		return []tier{t}, cfg
+	}
+
+	wanted := make(map[string]bool, len(resolved))
+	for _, address := range resolved {
+		wanted[address] = true
+	}
+
+	out := make([]tier, 0, len(tiers)+1)
+	for _, t := range tiers {
+		kept := tier{startDelays: t.startDelays}
+		for _, address := range t.addresses {
+			if wanted[address] {
+				kept.addresses = append(kept.addresses, address)
+			}
+		}
+
+		for _, address := range kept.addresses {
+			delete(wanted, address)
+		}
+
+		out = append(out, kept)
+	}
+
+	var rest []string
+	for _, address := range resolved {
+		if wanted[address] {
+			rest = append(rest, address)
+		}
+	}
+
+	return append(out, tier{addresses: rest}), cfg
+}
+
+// raceTiers races one tier after the other as described by GetTiered.
+func raceTiers(ctx context.Context, getter Getter, tiers []tier, key string, cfg config) (value, source string, err error) {
+	if err := validate[string](getter, key); err != nil {
+		return "", "", err
+	}
+
+	if err := checkDeadline(ctx, cfg.deadlineSlack); err != nil {
+		return "", "", err
+	}
+
+	tiers, cfg = resolveTiers(tiers, key, cfg)
+	var failures []AddressError
+	var lastErr error
+	for _, t := range tiers {
+		if len(t.addresses) == 0 {
+			continue
+		}
+
+		tcfg := cfg
+		if t.startDelays != nil {
+			tcfg.startDelays = t.startDelays
+		}
+
+		value, source, err := race[string](ctx, getter, t.addresses, key, tcfg)
+		if err == nil {
+			return value, source, nil
+		}
+
+		var multiErr *MultiError
+		switch {
+		case errors.As(err, &multiErr) && ctx.Err() == nil:
+			failures = append(failures, multiErr.Errors...)
+		case errors.Is(err, ErrAllCircuitsOpen), errors.Is(err, ErrNoHealthyAddresses), errors.Is(err, ErrKeyNotFound):
+		default:
+			return "", "", err
+		}
+
+		lastErr = err
+	}
+
+	if len(failures) > 0 {
+		return "", "", &MultiError{Errors: failures}
+	}
+
+	return "", "", lastErr
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGetTiered(t *testing.T) {
+	tiers := [][]string{{"local1", "local2"}, {"remote1", "remote2"}}
+
+	tests := []struct {
+		name      string
+		responses map[string]map[string]Response
+		tiers     [][]string
+		wantValue string
+		wantErr   bool
+		wantCalls map[string]int
+	}{
+		{
+			name: "успех первого яруса — второй не запрашивается",
+			responses: map[string]map[string]Response{
+				"local1":  {"key1": {Error: errors.New("connection error")}},
+				"local2":  {"key1": {Value: "local", Delay: 20 * time.Millisecond}},
+				"remote1": {"key1": {Value: "remote"}},
+				"remote2": {"key1": {Value: "remote"}},
+			},
+			tiers:     tiers,
+			wantValue: "local",
+			wantCalls: map[string]int{"local1": 1, "local2": 1, "remote1": 0, "remote2": 0},
+		},
+		{
+			name: "первый ярус падает целиком — переход ко второму",
+			responses: map[string]map[string]Response{
+				"local1":  {"key1": {Error: errors.New("connection error")}},
+				"local2":  {},
+				"remote1": {"key1": {Error: errors.New("connection error")}},
+				"remote2": {"key1": {Value: "remote", Delay: 20 * time.Millisecond}},
+			},
+			tiers:     tiers,
+			wantValue: "remote",
+			wantCalls: map[string]int{"local1": 1, "local2": 1, "remote1": 1, "remote2": 1},
+		},
+		{
+			name: "все ярусы падают",
+			responses: map[string]map[string]Response{
+				"local1":  {},
+				"local2":  {},
+				"remote1": {},
+				"remote2": {},
+			},
+			tiers:     tiers,
+			wantErr:   true,
+			wantCalls: map[string]int{"local1": 1, "local2": 1, "remote1": 1, "remote2": 1},
+		},
+		{
+			name:      "пустые ярусы",
+			responses: map[string]map[string]Response{},
+			tiers:     [][]string{{}, nil},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := newCountingGetter(NewMockGetter(tt.responses))
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetTiered(ctx, getter, tt.tiers, "key1")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetTiered() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetTiered() = %q, want %q", got, tt.wantValue)
+			}
+
+			for address, want := range tt.wantCalls {
+				if n := getter.Calls(address); n != want {
+					t.Fatalf("calls to %s = %d, want %d", address, n, want)
+				}
+			}
+
+			var multiErr *MultiError
+			if tt.wantErr && (!errors.As(err, &multiErr) || len(multiErr.Errors) != 4) {
+				t.Fatalf("GetTiered() error = %v, want a MultiError covering all 4 addresses", err)
+			}
+		})
+	}
+
+	t.Run("ключа нет в первом ярусе — переход ко второму", func(t *testing.T) {
+		getter := &MapGetter{Responses: map[string]map[string]string{"remote1": {"key1": "remote"}}}
+
+		got, err := GetTiered(context.Background(), getter, tiers, "key1")
+		if err != nil || got != "remote" {
+			t.Fatalf("GetTiered() = (%q, %v), want (%q, nil)", got, err, "remote")
+		}
+
+		if _, err := GetTiered(context.Background(), getter, tiers, "missing"); err != ErrKeyNotFound {
+			t.Fatalf("GetTiered() error = %v, want %v", err, ErrKeyNotFound)
+		}
+	})
+}
+
+func TestGetTieredWithAddressResolver(t *testing.T) {
+	tiers := [][]string{{"a"}, {"b"}, {"c"}}
+
+	tests := []struct {
+		name      string
+		resolved  []string
+		wantOrder []string
+	}{
+		{name: "адрес вне ярусов запрашивается один раз", resolved: []string{"x"}, wantOrder: []string{"x"}},
+		{name: "ярусы сохраняют свой порядок", resolved: []string{"x", "c", "a"}, wantOrder: []string{"a", "c", "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getter := &orderRecordingGetter{Getter: NewMockGetter(nil)}
+			resolve := WithAddressResolver(func(string) []string { return tt.resolved })
+
+			_, err := GetTiered(context.Background(), getter, tiers, "key1", resolve)
+			var multiErr *MultiError
+			if !errors.As(err, &multiErr) || len(multiErr.Errors) != len(tt.wantOrder) {
+				t.Fatalf("GetTiered() error = %v, want a MultiError with %d failures", err, len(tt.wantOrder))
+			}
+
+			if !slices.Equal(getter.order, tt.wantOrder) {
+				t.Fatalf("queried %v, want %v", getter.order, tt.wantOrder)
+			}
+
+			endpoints := []Endpoint{{Address: "a"}, {Address: "b", Tier: 1}, {Address: "c", Tier: 2}}
+			var planned []string
+			for _, a := range PlanEndpoints(endpoints, "key1", resolve).Attempts {
+				planned = append(planned, a.Address)
+			}
+
+			if !slices.Equal(planned, tt.wantOrder) {
+				t.Fatalf("PlanEndpoints() planned %v, want %v", planned, tt.wantOrder)
+			}
+		})
+	}
+}
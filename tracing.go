@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Tracer starts spans for Get. It is deliberately smaller than an
+// OpenTelemetry trace.Tracer, so that this package does not depend on OTel;
+// adapting one takes a few lines that forward Start and translate the
+// attributes.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span)
+}
+
+type Span interface {
+	AddEvent(name string, attrs ...slog.Attr)
+	End()
+}
+
+// WithTracer records a "Get" span for the whole operation with an "attempt"
+// child span per getter call. The operation span gets a "winner" event naming
+// the address that answered, or a "failed" event with the error; attempt
+// spans get a "failed" or "cancelled" event when they do not succeed. Every
+// span is ended, including those of attempts cancelled by the winner.
+func WithTracer(tracer Tracer) Option {
+	return func(c *config) {
+		c.tracer = tracer
+	}
+}
+
+func endOperation(span Span, source string, err error) {
+	if err != nil {
+		span.AddEvent("failed", slog.Any("error", err))
+	} else if source != "" {
+		span.AddEvent("winner", slog.String("address", source))
+	}
+
+	span.End()
+}
+
+// endAttempt ends the span of a getter call. ctx is the context of the
+// operation, telling a cancelled attempt apart from a failed one.
+func endAttempt(ctx context.Context, span Span, err error) {
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		span.AddEvent("cancelled", slog.Any("error", err))
+	default:
+		span.AddEvent("failed", slog.Any("error", err))
+	}
+
+	span.End()
+}
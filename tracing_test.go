@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+type spanKey struct{}
+
+type recordedSpan struct {
+	name   string
+	parent *recordedSpan
+	attrs  map[string]string
+	events []string
+
+	mu    *sync.Mutex
+	ended bool
+}
+
+func (s *recordedSpan) AddEvent(name string, attrs ...slog.Attr) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, name)
+}
+
+func (s *recordedSpan) End() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.ended = true
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string, attrs ...slog.Attr) (context.Context, Span) {
+	parent, _ := ctx.Value(spanKey{}).(*recordedSpan)
+	span := &recordedSpan{name: name, parent: parent, attrs: map[string]string{}, mu: &r.mu}
+	for _, a := range attrs {
+		span.attrs[a.Key] = a.Value.String()
+	}
+
+	r.mu.Lock()
+	r.spans = append(r.spans, span)
+	r.mu.Unlock()
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (r *recordingTracer) allEnded() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, span := range r.spans {
+		if !span.ended {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestGetWithTracer(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"addr1": {"key1": {Error: errors.New("connection error")}},
+		"addr2": {"key1": {Value: "value2", Delay: 20 * time.Millisecond}},
+		"addr3": {"key1": {Value: "value3", Delay: time.Second}},
+	})
+	tracer := &recordingTracer{}
+
+	got, err := Get(context.Background(), mock, []string{"addr1", "addr2", "addr3"}, "key1", WithTracer(tracer))
+	if err != nil || got != "value2" {
+		t.Fatalf("Get() = (%q, %v), want (%q, nil)", got, err, "value2")
+	}
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	for !tracer.allEnded() {
+		if time.Now().After(deadline) {
+			t.Fatal("not every span was ended")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	tracer.mu.Lock()
+	defer tracer.mu.Unlock()
+
+	if len(tracer.spans) != 4 {
+		t.Fatalf("recorded %d spans, want 1 operation and 3 attempts", len(tracer.spans))
+	}
+
+	root := tracer.spans[0]
+	if root.name != "Get" || root.parent != nil || root.attrs["key"] != "key1" {
+		t.Fatalf("first span = %q with parent %v and attrs %v, want root Get span for key1", root.name, root.parent, root.attrs)
+	}
+
+	if len(root.events) != 1 || root.events[0] != "winner" {
+		t.Fatalf("operation span events = %v, want [winner]", root.events)
+	}
+
+	wantEvents := map[string][]string{
+		"addr1": {"failed"},
+		"addr2": nil,
+		"addr3": {"cancelled"},
+	}
+	for _, span := range tracer.spans[1:] {
+		if span.name != "attempt" || span.parent != root {
+			t.Fatalf("span %q has parent %v, want attempt span under the operation", span.name, span.parent)
+		}
+
+		address := span.attrs["address"]
+		want, ok := wantEvents[address]
+		if !ok {
+			t.Fatalf("attempt span for unexpected address %q", address)
+		}
+		delete(wantEvents, address)
+
+		if len(span.events) != len(want) || (len(want) > 0 && span.events[0] != want[0]) {
+			t.Fatalf("attempt span for %s has events %v, want %v", address, span.events, want)
+		}
+	}
+}
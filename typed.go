@@ -0,0 +1,15 @@
+package main
+
+import "context"
+
+// TypedGetter is the generic counterpart of Getter for backends that return
+// decoded values instead of strings.
+type TypedGetter[T any] interface {
+	Get(ctx context.Context, address, key string) (T, error)
+}
+
+// GetTyped races addresses exactly like Get, but over values of type T.
+func GetTyped[T any](ctx context.Context, getter TypedGetter[T], addresses []string, key string) (T, error) {
+	value, _, err := race(ctx, getter, addresses, key, config{})
+	return value, err
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type TypedResponse[T any] struct {
+	Value T
+	Error error
+	Delay time.Duration
+}
+
+type MockTypedGetter[T any] struct {
+	Responses map[string]map[string]TypedResponse[T]
+}
+
+func (m *MockTypedGetter[T]) Get(ctx context.Context, address, key string) (T, error) {
+	var zero T
+	if responses, exists := m.Responses[address]; exists {
+		if resp, keyExists := responses[key]; keyExists {
+			if resp.Delay > 0 {
+				select {
+				case <-time.After(resp.Delay):
+				case <-ctx.Done():
+					return zero, ctx.Err()
+				}
+			}
+
+			if resp.Error != nil {
+				return zero, resp.Error
+			}
+
+			return resp.Value, nil
+		}
+	}
+
+	return zero, errors.New("key not found")
+}
+
+type user struct {
+	ID   int
+	Name string
+}
+
+func TestGetTypedStruct(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]map[string]TypedResponse[user]
+		addresses []string
+		ttl       time.Duration
+		wantValue user
+		wantErr   bool
+		wantErrIs error
+	}{
+		{
+			name: "первый адрес падает, второй успешен",
+			responses: map[string]map[string]TypedResponse[user]{
+				"addr1": {"key1": {Error: errors.New("connection error")}},
+				"addr2": {"key1": {Value: user{ID: 2, Name: "bob"}}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			ttl:       50 * time.Millisecond,
+			wantValue: user{ID: 2, Name: "bob"},
+		},
+		{
+			name: "быстрый адрес побеждает медленный",
+			responses: map[string]map[string]TypedResponse[user]{
+				"addr1": {"key1": {Value: user{ID: 1}, Delay: 200 * time.Millisecond}},
+				"addr2": {"key1": {Value: user{ID: 2}, Delay: 20 * time.Millisecond}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			ttl:       300 * time.Millisecond,
+			wantValue: user{ID: 2},
+		},
+		{
+			name: "отмена контекста",
+			responses: map[string]map[string]TypedResponse[user]{
+				"addr1": {"key1": {Value: user{ID: 1}, Delay: 200 * time.Millisecond}},
+			},
+			addresses: []string{"addr1"},
+			ttl:       50 * time.Millisecond,
+			wantErr:   true,
+			wantErrIs: context.Canceled,
+		},
+		{
+			name:      "пустой список адресов",
+			responses: map[string]map[string]TypedResponse[user]{},
+			addresses: []string{},
+			ttl:       50 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ttl)
+			defer cancel()
+
+			got, err := GetTyped[user](ctx, &MockTypedGetter[user]{Responses: tt.responses}, tt.addresses, "key1")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("GetTyped() error = %v, want errors.Is(err, %v) == true", err, tt.wantErrIs)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetTyped() = %+v, want %+v", got, tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestGetTypedInt(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses map[string]map[string]TypedResponse[int]
+		addresses []string
+		wantValue int
+		wantErr   bool
+	}{
+		{
+			name: "смешанные ошибки и один успех",
+			responses: map[string]map[string]TypedResponse[int]{
+				"addr1": {},
+				"addr2": {"key1": {Error: errors.New("connection error")}},
+				"addr3": {"key1": {Value: 42}},
+			},
+			addresses: []string{"addr1", "addr2", "addr3"},
+			wantValue: 42,
+		},
+		{
+			name: "все адреса падают",
+			responses: map[string]map[string]TypedResponse[int]{
+				"addr1": {"key1": {Error: errors.New("error 1")}},
+				"addr2": {"key1": {Error: errors.New("error 2")}},
+			},
+			addresses: []string{"addr1", "addr2"},
+			wantValue: 0,
+			wantErr:   true,
+		},
+		{
+			name:      "пустой список адресов — нулевое значение",
+			responses: map[string]map[string]TypedResponse[int]{},
+			addresses: nil,
+			wantValue: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetTyped[int](ctx, &MockTypedGetter[int]{Responses: tt.responses}, tt.addresses, "key1")
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetTyped() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.wantValue {
+				t.Fatalf("GetTyped() = %d, want %d", got, tt.wantValue)
+			}
+		})
+	}
+}
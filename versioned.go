@@ -0,0 +1,83 @@
+package main
+
+import "context"
+
+// VersionedGetter is a Getter for backends that keep a version with every
+// value, so that replicas holding different writes can be told apart.
+type VersionedGetter interface {
+	Get(ctx context.Context, address, key string) (value string, version int64, err error)
+}
+
+// versioned is a value together with its version.
+type versioned struct {
+	value   string
+	version int64
+}
+
+// versionedAdapter presents a VersionedGetter as a TypedGetter, so the
+// versioned reads share the engine of the plain ones.
+type versionedAdapter struct {
+	vg VersionedGetter
+}
+
+func (a versionedAdapter) Get(ctx context.Context, address, key string) (versioned, error) {
+	value, version, err := a.vg.Get(ctx, address, key)
+	return versioned{value: value, version: version}, err
+}
+
+// GetLatest queries every address and returns the value with the highest
+// version, for last-write-wins reads. Among values of the same version the
+// one that arrived first wins. Failed addresses are ignored unless all of
+// them fail. Options choosing the addresses apply as in Get; options pacing
+// the starts, such as WithMaxConcurrency and WithHedgeDelay, do not: every
+// address is queried at once.
+func GetLatest(ctx context.Context, vg VersionedGetter, addresses []string, key string, opts ...Option) (string, int64, error) {
+	cfg := newConfig(opts)
+	var getter TypedGetter[versioned]
+	if vg != nil {
+		getter = versionedAdapter{vg: vg}
+	}
+
+	if err := validate(getter, key); err != nil {
+		return "", 0, err
+	}
+
+	cfg = cfg.withContext(ctx)
+	if err := checkDeadline(ctx, cfg.deadlineSlack); err != nil {
+		return "", 0, err
+	}
+
+	addresses, err := prepare(addresses, key, cfg)
+	if err != nil || len(addresses) == 0 {
+		return "", 0, err
+	}
+
+	results := make(chan result[versioned], len(addresses))
+	for i, address := range addresses {
+		go func() {
+			value, err := query[versioned](ctx, getter, address, key, cfg)
+			results <- result[versioned]{index: i, address: address, value: value, err: err}
+		}()
+	}
+
+	var latest *versioned
+	errs := make([]AddressError, len(addresses))
+	for range addresses {
+		select {
+		case r := <-results:
+			if r.err != nil {
+				errs[r.index] = AddressError{Address: r.address, Err: r.err}
+			} else if latest == nil || r.value.version > latest.version {
+				latest = &r.value
+			}
+		case <-ctx.Done():
+			return "", 0, canceled(ctx)
+		}
+	}
+
+	if latest == nil {
+		return "", 0, &MultiError{Errors: errs}
+	}
+
+	return latest.value, latest.version, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type versionedResponse struct {
+	Value   string
+	Version int64
+	Error   error
+	Delay   time.Duration
+}
+
+type MockVersionedGetter struct {
+	Responses map[string]versionedResponse
+}
+
+func (m *MockVersionedGetter) Get(ctx context.Context, address, key string) (string, int64, error) {
+	resp, ok := m.Responses[address]
+	if !ok {
+		return "", 0, errors.New("key not found")
+	}
+
+	select {
+	case <-time.After(resp.Delay):
+	case <-ctx.Done():
+		return "", 0, ctx.Err()
+	}
+
+	return resp.Value, resp.Version, resp.Error
+}
+
+func TestGetLatest(t *testing.T) {
+	open := NewBreaker(1, time.Minute)
+	open.record("addr2", errors.New("connection error"))
+
+	tests := []struct {
+		name        string
+		responses   map[string]versionedResponse
+		opts        []Option
+		wantValue   string
+		wantVersion int64
+		wantErr     bool
+	}{
+		{
+			name: "явный победитель по версии",
+			responses: map[string]versionedResponse{
+				"addr1": {Value: "old", Version: 1},
+				"addr2": {Value: "new", Version: 3, Delay: 20 * time.Millisecond},
+				"addr3": {Value: "mid", Version: 2},
+			},
+			wantValue:   "new",
+			wantVersion: 3,
+		},
+		{
+			name: "равные версии — побеждает первый ответивший",
+			responses: map[string]versionedResponse{
+				"addr1": {Value: "late", Version: 5, Delay: 40 * time.Millisecond},
+				"addr2": {Value: "early", Version: 5, Delay: 10 * time.Millisecond},
+				"addr3": {Value: "old", Version: 4},
+			},
+			wantValue:   "early",
+			wantVersion: 5,
+		},
+		{
+			name: "упавшие адреса не мешают",
+			responses: map[string]versionedResponse{
+				"addr1": {Error: errors.New("connection error")},
+				"addr2": {Value: "only", Version: 1},
+			},
+			wantValue:   "only",
+			wantVersion: 1,
+		},
+		{
+			name: "нездоровый адрес не запрашивается",
+			responses: map[string]versionedResponse{
+				"addr1": {Value: "old", Version: 1},
+				"addr2": {Value: "new", Version: 3},
+				"addr3": {Value: "mid", Version: 2},
+			},
+			opts:        []Option{WithHealthChecker(func(address string) bool { return address != "addr2" })},
+			wantValue:   "mid",
+			wantVersion: 2,
+		},
+		{
+			name: "адрес с открытой цепью не запрашивается",
+			responses: map[string]versionedResponse{
+				"addr1": {Value: "old", Version: 1},
+				"addr2": {Value: "new", Version: 3},
+				"addr3": {Value: "mid", Version: 2},
+			},
+			opts:        []Option{WithBreaker(open)},
+			wantValue:   "mid",
+			wantVersion: 2,
+		},
+		{
+			name:      "все адреса падают",
+			responses: map[string]versionedResponse{},
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, version, err := GetLatest(ctx, &MockVersionedGetter{Responses: tt.responses}, []string{"addr1", "addr2", "addr3"}, "key1", tt.opts...)
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetLatest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.wantValue || version != tt.wantVersion {
+				t.Fatalf("GetLatest() = (%q, %d), want (%q, %d)", got, version, tt.wantValue, tt.wantVersion)
+			}
+		})
+	}
+
+	if _, _, err := GetLatest(context.Background(), nil, []string{"addr1"}, "key1"); !errors.Is(err, ErrNilGetter) {
+		t.Fatalf("GetLatest() with a nil getter error = %v, want %v", err, ErrNilGetter)
+	}
+}
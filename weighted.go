@@ -0,0 +1,82 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+// weightStagger is how much later each lower weight class starts than the
+// class above it, unless WithWeightStagger says otherwise.
+const weightStagger = 2 * time.Millisecond
+
+// WithWeightStagger makes GetWeighted and GetEndpoints start every lower
+// weight class d after the class above it, instead of weightStagger. The
+// default only orders the starts; against backends answering in tens of
+// milliseconds, a stagger close to their latency is what lets the heavier
+// ones win. A d of zero or less keeps the default.
+func WithWeightStagger(d time.Duration) Option {
+	return func(c *config) {
+		c.weightStagger = d
+	}
+}
+
+// stagger returns the delay between weight classes.
+func (c config) stagger() time.Duration {
+	if c.weightStagger > 0 {
+		return c.weightStagger
+	}
+
+	return weightStagger
+}
+
+type WeightedAddress struct {
+	Address string
+	Weight  int
+}
+
+// GetWeighted races addresses like Get, but starts higher-weight addresses
+// first: every lower weight class is started weightStagger, or the delay
+// set by WithWeightStagger, after the class above it. Addresses of equal
+// weight start together in input order, so an equal or all-zero weight set
+// behaves exactly like Get.
+func GetWeighted(ctx context.Context, getter Getter, addresses []WeightedAddress, key string, opts ...Option) (string, error) {
+	cfg := newConfig(opts)
+	t := weightedTier(addresses, cfg.stagger())
+	cfg.startDelays = t.startDelays
+	value, _, err := race[string](ctx, getter, t.addresses, key, cfg)
+	return value, err
+}
+
+// weightedTier orders addresses by descending weight and staggers the start
+// of every weight class as described by GetWeighted.
+func weightedTier(addresses []WeightedAddress, stagger time.Duration) tier {
+	sorted := slices.Clone(addresses)
+	slices.SortStableFunc(sorted, func(a, b WeightedAddress) int {
+		return cmp.Compare(b.Weight, a.Weight)
+	})
+
+	ordered := make([]string, len(sorted))
+	delays := make(map[string]time.Duration, len(sorted))
+	class := 0
+	for i, wa := range sorted {
+		ordered[i] = wa.Address
+		if i > 0 && wa.Weight != sorted[i-1].Weight {
+			class++
+		}
+
+		// A duplicated address keeps the delay of its heaviest entry,
+		// which is the one dedup keeps.
+		if _, ok := delays[wa.Address]; !ok {
+			delays[wa.Address] = time.Duration(class) * stagger
+		}
+	}
+
+	t := tier{addresses: ordered}
+	if class > 0 {
+		t.startDelays = delays
+	}
+
+	return t
+}
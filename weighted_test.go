@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+type firstCallGetter struct {
+	Getter
+
+	once  sync.Once
+	first string
+}
+
+func (f *firstCallGetter) Get(ctx context.Context, address, key string) (string, error) {
+	f.once.Do(func() { f.first = address })
+	return f.Getter.Get(ctx, address, key)
+}
+
+func TestGetWeightedQueriesHeavyFirst(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"light":  {"key1": {Value: "light", Delay: 5 * time.Millisecond}},
+		"medium": {"key1": {Value: "medium", Delay: 5 * time.Millisecond}},
+		"heavy":  {"key1": {Value: "heavy", Delay: 5 * time.Millisecond}},
+	})
+	addresses := []WeightedAddress{
+		{Address: "light", Weight: 1},
+		{Address: "medium", Weight: 5},
+		{Address: "heavy", Weight: 10},
+	}
+
+	const runs = 50
+	heavyFirst := 0
+	for range runs {
+		getter := &firstCallGetter{Getter: mock}
+
+		got, err := GetWeighted(context.Background(), getter, addresses, "key1")
+		if err != nil {
+			t.Fatalf("GetWeighted() error = %v", err)
+		}
+
+		if getter.first == "heavy" && got == "heavy" {
+			heavyFirst++
+		}
+	}
+
+	if heavyFirst < runs*9/10 {
+		t.Fatalf("heavy address queried first in %d of %d runs, want at least 90%%", heavyFirst, runs)
+	}
+}
+
+func TestGetWeightedEqualWeights(t *testing.T) {
+	tests := []struct {
+		name      string
+		weight    int
+		wantValue string
+	}{
+		{name: "нулевые веса", weight: 0, wantValue: "value2"},
+		{name: "равные веса", weight: 3, wantValue: "value2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := NewMockGetter(map[string]map[string]Response{
+				"addr1": {"key1": {Value: "value1", Delay: 200 * time.Millisecond}},
+				"addr2": {"key1": {Value: "value2", Delay: 50 * time.Millisecond}},
+			})
+			getter := newCountingGetter(mock)
+			addresses := []WeightedAddress{{"addr1", tt.weight}, {"addr2", tt.weight}}
+
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+
+			got, err := GetWeighted(ctx, getter, addresses, "key1")
+
+			if err != nil || got != tt.wantValue {
+				t.Fatalf("GetWeighted() = (%q, %v), want (%q, nil)", got, err, tt.wantValue)
+			}
+
+			if n := getter.MaxInFlight(); n != 2 {
+				t.Fatalf("max in flight = %d, want both addresses raced at once", n)
+			}
+		})
+	}
+}
+
+func TestGetWeightedDelaysFollowAddresses(t *testing.T) {
+	// The ring puts light before heavy for key1, the reverse of their
+	// weight order.
+	hash := map[string]uint64{"key1": 0, "light#0": 1, "heavy#0": 2}
+	ring := NewRingWithHash([]string{"heavy", "light"}, 1, func(s string) uint64 { return hash[s] })
+	addresses := []WeightedAddress{{Address: "heavy", Weight: 2}, {Address: "light", Weight: 1}}
+
+	t.Run("план", func(t *testing.T) {
+		p := PlanEndpoints([]Endpoint{{Address: "heavy", Weight: 2}, {Address: "light", Weight: 1}}, "key1", WithRing(ring))
+		want := []PlannedAttempt{{Address: "light", Start: weightStagger}, {Address: "heavy"}}
+		if !slices.Equal(p.Attempts, want) {
+			t.Fatalf("Attempts = %+v, want %+v", p.Attempts, want)
+		}
+	})
+
+	t.Run("запросы", func(t *testing.T) {
+		mock := NewMockGetter(map[string]map[string]Response{
+			"heavy": {"key1": {Error: errors.New("connection error")}},
+			"light": {"key1": {Value: "light"}},
+		})
+		getter := &startRecordingGetter{Getter: mock, starts: map[string]time.Duration{}}
+
+		getter.begin = time.Now()
+		if got, err := GetWeighted(context.Background(), getter, addresses, "key1", WithRing(ring)); err != nil || got != "light" {
+			t.Fatalf("GetWeighted() = (%q, %v), want (%q, nil)", got, err, "light")
+		}
+
+		if d := getter.starts["light"]; d < weightStagger {
+			t.Fatalf("light started after %v, want no earlier than %v", d, weightStagger)
+		}
+	})
+}
+
+func TestWithWeightStagger(t *testing.T) {
+	mock := NewMockGetter(map[string]map[string]Response{
+		"heavy": {"key1": {Value: "heavy", Delay: 30 * time.Millisecond}},
+		"light": {"key1": {Value: "light"}},
+	})
+	addresses := []WeightedAddress{{Address: "light", Weight: 1}, {Address: "heavy", Weight: 2}}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{name: "короткий шаг по умолчанию — выигрывает быстрый", want: "light"},
+		{name: "шаг больше задержки — выигрывает тяжёлый", opts: []Option{WithWeightStagger(100 * time.Millisecond)}, want: "heavy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetWeighted(context.Background(), mock, addresses, "key1", tt.opts...)
+			if err != nil || got != tt.want {
+				t.Fatalf("GetWeighted() = (%q, %v), want (%q, nil)", got, err, tt.want)
+			}
+
+			endpoints := []Endpoint{{Address: "light", Weight: 1}, {Address: "heavy", Weight: 2}}
+			want := newConfig(tt.opts).stagger()
+			if p := PlanEndpoints(endpoints, "key1", tt.opts...); p.Attempts[1].Start != want {
+				t.Fatalf("PlanEndpoints() starts light at %v, want %v", p.Attempts[1].Start, want)
+			}
+		})
+	}
+}